@@ -0,0 +1,57 @@
+package spinner
+
+import "strings"
+
+// WithActivityLines shows the last n lines passed to Activity below the
+// spinner's line, scrolling as new ones arrive, as installers do to show
+// what they are working on. The lines are repainted in place with the
+// spinner and, unless WithPersistActivity is set, erased when it stops.
+// Lines longer than the terminal is wide are truncated so that they do not
+// wrap. Activity lines are only drawn by animating spinners.
+func WithActivityLines(n int) Option {
+	return func(s *Spinner) {
+		s.activityLines = n
+	}
+}
+
+// WithPersistActivity leaves the activity lines on screen when the spinner
+// stops, above the line that Success, Fail, Warn or Stopf prints.
+func WithPersistActivity(persist bool) Option {
+	return func(s *Spinner) {
+		s.persistActivity = persist
+	}
+}
+
+// Activity adds line to the activity lines shown below the spinner with
+// WithActivityLines, dropping the oldest once there are more than fit. The
+// next frame drawn shows it. Line breaks in line are replaced as they are
+// in messages. It does nothing without WithActivityLines.
+func (s *Spinner) Activity(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.activityLines <= 0 {
+		return
+	}
+	if len(s.activity) >= s.activityLines {
+		s.activity = append(s.activity[:0], s.activity[len(s.activity)-s.activityLines+1:]...)
+	}
+	s.activity = append(s.activity, s.sanitize(line))
+}
+
+// withActivity returns line, of display width w, followed by the activity
+// lines, and the width of the widest of them.
+func (s *Spinner) withActivity(line string, w int) (string, int) {
+	if len(s.activity) == 0 || s.plain {
+		return line, w
+	}
+	var b strings.Builder
+	b.WriteString(line)
+	for _, a := range s.activity {
+		if s.columns > 1 {
+			a = s.truncate(a, s.columns-1, TruncateTail)
+		}
+		b.WriteString("\n" + a)
+		w = max(w, s.width(a))
+	}
+	return b.String(), w
+}
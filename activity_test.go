@@ -0,0 +1,65 @@
+package spinner
+
+import "testing"
+
+func TestActivityLines(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	t.Setenv("COLUMNS", "12")
+	tests := []struct {
+		name    string
+		opts    []Option
+		end     func(*Spinner)
+		running string // screen after the activity is drawn
+		stopped string
+	}{
+		{
+			name:    "cleared",
+			end:     func(s *Spinner) { s.Success("done") },
+			running: "$ install\n- working\nstep 2\nstep 3 has…\nstep 4",
+			stopped: "$ install\n✔ done",
+		},
+		{
+			name:    "persisted",
+			opts:    []Option{WithPersistActivity(true)},
+			end:     func(s *Spinner) { s.Success("done") },
+			running: "$ install\n- working\nstep 2\nstep 3 has…\nstep 4",
+			stopped: "$ install\nstep 2\nstep 3 has a long name\nstep 4\n✔ done",
+		},
+		{
+			name:    "stop",
+			end:     (*Spinner).Stop,
+			running: "$ install\n- working\nstep 2\nstep 3 has…\nstep 4",
+			stopped: "$ install",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := new(screen)
+			sc.Write([]byte("$ install\n"))
+			opts := []Option{WithWriter(sc), WithForceTTY(true), WithFrames([]string{"-"}), WithMessage("working"), WithColorMode(ColorNever), WithActivityLines(3)}
+			s := New(append(opts, tt.opts...)...)
+			s.loop = manualLoop
+			s.Start()
+			s.tick()
+			for _, line := range []string{"step 1", "step 2", "step 3 has a long name", "step 4"} {
+				s.Activity(line)
+			}
+			s.tick()
+			if got := sc.String(); got != tt.running {
+				t.Errorf("screen while running:\n%s\nwant:\n%s", got, tt.running)
+			}
+			tt.end(s)
+			if got := sc.String(); got != tt.stopped {
+				t.Errorf("screen after stopping:\n%s\nwant:\n%s", got, tt.stopped)
+			}
+		})
+	}
+}
+
+func TestActivityWithoutLines(t *testing.T) {
+	s := New()
+	s.Activity("ignored")
+	if s.activity != nil {
+		t.Errorf("activity = %q without WithActivityLines", s.activity)
+	}
+}
@@ -0,0 +1,32 @@
+package spinner
+
+import "time"
+
+// WithAdaptiveInterval lets the spinner stretch its interval when writes to
+// the terminal are slow, as over a high-latency ssh connection, so that a
+// fast animation does not saturate the link with redraws. The time each
+// frame takes to write is measured and smoothed, and the interval is
+// lengthened to twice that time when it would otherwise be shorter, then
+// shrinks back as writes speed up. The interval in effect is kept between
+// minInterval and maxInterval and reported by Stats.
+func WithAdaptiveInterval(minInterval, maxInterval time.Duration) Option {
+	return func(s *Spinner) {
+		s.adaptMin, s.adaptMax = minInterval, maxInterval
+	}
+}
+
+// measureWrite adds a frame write that took d to the smoothed write
+// latency, giving it a quarter of the weight so that a single slow write
+// does not stretch the interval on its own.
+func (s *Spinner) measureWrite(d time.Duration) {
+	s.latency += (d - s.latency) / 4
+}
+
+// adapt returns the interval to wait before the next frame in place of
+// base, stretched to the write latency if WithAdaptiveInterval was given.
+func (s *Spinner) adapt(base time.Duration) time.Duration {
+	if s.adaptMax <= 0 {
+		return base
+	}
+	return min(max(base, 2*s.latency, s.adaptMin), s.adaptMax)
+}
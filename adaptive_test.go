@@ -0,0 +1,76 @@
+package spinner
+
+import (
+	"testing"
+	"time"
+)
+
+// laggyWriter is a terminal on which every write takes delay on clock.
+type laggyWriter struct {
+	clock *fakeClock
+	delay time.Duration
+}
+
+func (w *laggyWriter) Write(p []byte) (int, error) {
+	w.clock.t = w.clock.t.Add(w.delay)
+	return len(p), nil
+}
+
+func TestAdaptiveInterval(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	w := &laggyWriter{clock: clock}
+	s := New(WithWriter(w), WithInterval(60*time.Millisecond), WithAdaptiveInterval(40*time.Millisecond, 500*time.Millisecond))
+	s.now = clock.now
+	s.loop = manualLoop
+	s.Start()
+	defer s.Stop()
+	// run draws n frames and returns the intervals tick chose after them.
+	run := func(n int) []time.Duration {
+		var waits []time.Duration
+		for range n {
+			wait, _ := s.tick()
+			clock.t = clock.t.Add(wait)
+			waits = append(waits, wait)
+		}
+		return waits
+	}
+
+	for _, wait := range run(10) {
+		if wait != 60*time.Millisecond {
+			t.Fatalf("fast writes: interval %v, want 60ms", wait)
+		}
+	}
+
+	// Writes of 150ms converge on an interval of 300ms without overshooting.
+	w.delay = 150 * time.Millisecond
+	waits := run(40)
+	for i := 1; i < len(waits); i++ {
+		if waits[i] < waits[i-1] || waits[i] > 300*time.Millisecond {
+			t.Fatalf("slow writes: intervals %v do not rise steadily to 300ms", waits)
+		}
+	}
+	if last := waits[len(waits)-1]; last < 295*time.Millisecond {
+		t.Errorf("slow writes: interval %v after 40 frames, want about 300ms", last)
+	}
+	if st := s.Stats(); st.Interval != waits[len(waits)-1] || st.WriteLatency < 145*time.Millisecond {
+		t.Errorf("Stats interval %v, latency %v", st.Interval, st.WriteLatency)
+	}
+
+	w.delay = time.Second
+	if waits := run(20); waits[len(waits)-1] != 500*time.Millisecond {
+		t.Errorf("very slow writes: interval %v, want the 500ms cap", waits[len(waits)-1])
+	}
+
+	// Once writes are fast again the interval falls back to 60ms.
+	w.delay = time.Millisecond
+	waits = run(40)
+	for i := 1; i < len(waits); i++ {
+		if waits[i] > waits[i-1] {
+			t.Fatalf("fast writes again: intervals %v do not fall steadily", waits)
+		}
+	}
+	if last := waits[len(waits)-1]; last != 60*time.Millisecond {
+		t.Errorf("fast writes again: interval %v, want 60ms", last)
+	}
+}
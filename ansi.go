@@ -0,0 +1,117 @@
+package spinner
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Escape sequences the spinner writes, for callers composing their own
+// output or color funcs.
+const (
+	HideCursor = "\033[?25l"
+	ShowCursor = "\033[?25h"
+	EraseLine  = "\033[2K"
+
+	Bold      = "\033[1m"
+	Dim       = "\033[2m"
+	Italic    = "\033[3m"
+	Underline = "\033[4m"
+)
+
+// SGR returns the Select Graphic Rendition sequence that sets the given
+// attributes, such as SGR(1, 38, 5, 208) for bold orange. With no codes it
+// resets all attributes.
+func SGR(codes ...int) string {
+	var b strings.Builder
+	b.WriteString("\033[")
+	for i, c := range codes {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(strconv.Itoa(c))
+	}
+	b.WriteByte('m')
+	return b.String()
+}
+
+// Background256 returns the escape sequence that sets the background to
+// color n of the 256-color palette, clamped to 0 to 255 as in Color256.
+func Background256(n int) string {
+	return SGR(48, 5, min(max(n, 0), 255))
+}
+
+// BackgroundRGB is like Background256 but sets a 24-bit color, clamped as
+// in RGB.
+func BackgroundRGB(r, g, b int) string {
+	c := func(v int) int { return min(max(v, 0), 255) }
+	return SGR(48, 2, c(r), c(g), c(b))
+}
+
+// Attr is a text attribute that ResetAttr can turn off on its own.
+type Attr int
+
+const (
+	AttrBold          Attr = 1
+	AttrDim           Attr = 2
+	AttrItalic        Attr = 3
+	AttrUnderline     Attr = 4
+	AttrBlink         Attr = 5
+	AttrReverse       Attr = 7
+	AttrStrikethrough Attr = 9
+)
+
+// Partial resets that, unlike Reset, leave every other attribute alone.
+const (
+	ResetForeground = "\033[39m"
+	ResetBackground = "\033[49m"
+)
+
+// ResetAttr returns the escape sequence that turns off attr and nothing
+// else, such as ending bold while keeping the color. Terminals turn bold
+// and dim off together. For an unknown attr it returns "".
+func ResetAttr(attr Attr) string {
+	switch attr {
+	case AttrBold, AttrDim:
+		return SGR(22)
+	case AttrItalic, AttrUnderline, AttrBlink, AttrReverse, AttrStrikethrough:
+		return SGR(int(attr) + 20)
+	}
+	return ""
+}
+
+// CombineSGR joins Select Graphic Rendition sequences, such as Bold,
+// Color256(208) and Background256(0), into one sequence with the same
+// effect, which saves the terminal parsing an escape prefix for each. It
+// returns the concatenation of seqs unchanged if any of them is not made
+// of SGR sequences alone.
+func CombineSGR(seqs ...string) string {
+	var params []string
+	for _, seq := range seqs {
+		for rest := seq; rest != ""; {
+			body, ok := strings.CutPrefix(rest, "\033[")
+			end := strings.IndexByte(body, 'm')
+			if !ok || end < 0 || strings.Trim(body[:end], "0123456789;") != "" {
+				return strings.Join(seqs, "")
+			}
+			if end == 0 {
+				params = append(params, "0")
+			} else {
+				params = append(params, body[:end])
+			}
+			rest = body[end+1:]
+		}
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// sgrSpan returns color as a single escape sequence, for the start of a
+// colored span. A color that is already one sequence is returned as is.
+func sgrSpan(color string) string {
+	if strings.Count(color, "\033") <= 1 {
+		return color
+	}
+	return CombineSGR(color)
+}
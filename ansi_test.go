@@ -0,0 +1,56 @@
+package spinner_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tmc/spinner"
+)
+
+func ExampleSGR() {
+	fmt.Printf("%q\n", spinner.SGR(1, 38, 5, 208))
+	fmt.Printf("%q\n", spinner.SGR())
+	fmt.Println(spinner.SGR(1) == spinner.Bold)
+	// Output:
+	// "\x1b[1;38;5;208m"
+	// "\x1b[m"
+	// true
+}
+
+func ExampleCombineSGR() {
+	fmt.Printf("%q\n", spinner.CombineSGR(spinner.Bold, spinner.Color256(208), spinner.Background256(0)))
+	fmt.Printf("%q\n", spinner.Bold+spinner.ResetAttr(spinner.AttrBold)+spinner.ResetBackground)
+	// Output:
+	// "\x1b[1;38;5;208;48;5;0m"
+	// "\x1b[1m\x1b[22m\x1b[49m"
+}
+
+func TestSGRSpans(t *testing.T) {
+	// A color that is one sequence is drawn exactly as before.
+	simple := []string{spinner.Red, spinner.Bold, spinner.Color256(208), spinner.RGB(1, 2, 3), spinner.Background256(4), spinner.SGR()}
+	for _, name := range spinner.ColorNames() {
+		c, _ := spinner.ColorByName(name)
+		simple = append(simple, c)
+	}
+	for _, c := range simple {
+		if got, want := spinner.Sprint([]string{"x"}, 0, spinner.RenderColor(c)), c+"x"+spinner.Reset; got != want {
+			t.Errorf("Sprint with color %q = %q, want %q", c, got, want)
+		}
+		if got := spinner.CombineSGR(c); got != c && c != spinner.SGR() {
+			t.Errorf("CombineSGR(%q) = %q", c, got)
+		}
+	}
+
+	// Several sequences are combined into one.
+	tests := []struct{ color, want string }{
+		{spinner.Bold + spinner.Red, "\033[1;38;5;9m"},
+		{spinner.Red + spinner.Background256(0) + spinner.Underline, "\033[38;5;9;48;5;0;4m"},
+		{spinner.SGR() + spinner.Italic, "\033[0;3m"},
+		{spinner.Bold + "\033]0;title\a", spinner.Bold + "\033]0;title\a"},
+	}
+	for _, tt := range tests {
+		if got, want := spinner.Sprint([]string{"x"}, 0, spinner.RenderColor(tt.color)), tt.want+"x"+spinner.Reset; got != want {
+			t.Errorf("Sprint with color %q = %q, want %q", tt.color, got, want)
+		}
+	}
+}
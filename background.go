@@ -0,0 +1,151 @@
+package spinner
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backgroundTimeout bounds how long DetectBackground waits for the terminal
+// to answer.
+const backgroundTimeout = 100 * time.Millisecond
+
+// DetectBackground makes a best-effort guess at whether the terminal behind
+// w has a light or dark background, and reports whether it could tell. If w
+// is a terminal, the terminal is asked for its background color with an
+// OSC 11 query, waiting at most 100ms for the answer. Otherwise, or if the
+// terminal does not answer, the COLORFGBG environment variable set by some
+// terminals is consulted.
+//
+// The terminal is only queried while the process is in the terminal's
+// foreground process group, so that a background job is not stopped for
+// touching it. Spinners given WithBackgroundHint(BackgroundAuto) use the
+// result, found once per process, to pick a default frame color.
+func DetectBackground(w io.Writer) (Background, bool) {
+	return detectBackground(NewTerm(w).ANSI())
+}
+
+// detectBackground is DetectBackground for a writer whose Term writes
+// escape sequences if ansi is set.
+func detectBackground(ansi bool) (Background, bool) {
+	if ansi {
+		if bg, ok := queryBackground(); ok {
+			return bg, true
+		}
+	}
+	return parseCOLORFGBG(os.Getenv("COLORFGBG"))
+}
+
+var (
+	autoBackgroundOnce sync.Once
+	autoBackgroundBG   Background
+)
+
+// autoBackground returns the background detected for the first spinner
+// that asks, which writes to a terminal, and the same answer to every later
+// one.
+func autoBackground() Background {
+	autoBackgroundOnce.Do(func() {
+		autoBackgroundBG, _ = detectBackground(true)
+	})
+	return autoBackgroundBG
+}
+
+// queryBackground asks the controlling terminal for its background color.
+// The OSC 11 query is followed by a primary device attributes query, which
+// every terminal answers, so a terminal that ignores OSC 11 is recognized
+// without waiting for the timeout. An answer that comes too late to be read
+// is discarded when the terminal's settings are restored, so that it does
+// not end up in the program's input.
+var queryBackground = func() (Background, bool) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return BackgroundUnknown, false
+	}
+	defer tty.Close()
+	if !isForeground(tty) {
+		return BackgroundUnknown, false
+	}
+	restore, err := makeRaw(tty)
+	if err != nil {
+		return BackgroundUnknown, false
+	}
+	defer restore()
+	if err := tty.SetReadDeadline(time.Now().Add(backgroundTimeout)); err != nil {
+		return BackgroundUnknown, false
+	}
+	if _, err := io.WriteString(tty, "\033]11;?\033\\\033[c"); err != nil {
+		return BackgroundUnknown, false
+	}
+	var reply []byte
+	buf := make([]byte, 64)
+	for !hasDeviceAttributes(reply) {
+		n, err := tty.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if err != nil {
+			break
+		}
+	}
+	return parseOSC11(reply)
+}
+
+// hasDeviceAttributes reports whether reply ends with an answer to the
+// primary device attributes query, ESC [ ? ... c.
+func hasDeviceAttributes(reply []byte) bool {
+	i := bytes.LastIndex(reply, []byte("\033[?"))
+	return i >= 0 && bytes.IndexByte(reply[i:], 'c') >= 0
+}
+
+// parseOSC11 finds an answer to an OSC 11 query, such as
+// ESC ] 11 ; rgb:ffff/ffff/dddd BEL, in reply and classifies the color.
+func parseOSC11(reply []byte) (Background, bool) {
+	i := bytes.Index(reply, []byte("\033]11;rgb:"))
+	if i < 0 {
+		return BackgroundUnknown, false
+	}
+	rest := string(reply[i+len("\033]11;rgb:"):])
+	if end := strings.IndexAny(rest, "\a\033"); end >= 0 {
+		rest = rest[:end]
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 {
+		return BackgroundUnknown, false
+	}
+	var rgb [3]float64
+	for j, p := range parts {
+		if len(p) == 0 || len(p) > 4 {
+			return BackgroundUnknown, false
+		}
+		n, err := strconv.ParseUint(p, 16, 16)
+		if err != nil {
+			return BackgroundUnknown, false
+		}
+		rgb[j] = float64(n) / float64(uint64(1)<<(4*len(p))-1)
+	}
+	// Relative luminance as defined by ITU-R BT.709.
+	if 0.2126*rgb[0]+0.7152*rgb[1]+0.0722*rgb[2] > 0.5 {
+		return BackgroundLight, true
+	}
+	return BackgroundDark, true
+}
+
+// parseCOLORFGBG classifies the background named by a COLORFGBG value such
+// as "15;0" or "0;default;15", whose last field is the background's color
+// number: 7 and 9 to 15 are light, the others dark.
+func parseCOLORFGBG(v string) (Background, bool) {
+	if v == "" {
+		return BackgroundUnknown, false
+	}
+	n, err := strconv.Atoi(v[strings.LastIndexByte(v, ';')+1:])
+	switch {
+	case err != nil || n < 0 || n > 15:
+		return BackgroundUnknown, false
+	case n == 7 || n >= 9:
+		return BackgroundLight, true
+	}
+	return BackgroundDark, true
+}
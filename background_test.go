@@ -0,0 +1,116 @@
+package spinner
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestParseOSC11(t *testing.T) {
+	tests := []struct {
+		reply string
+		want  Background
+		ok    bool
+	}{
+		{"\033]11;rgb:ffff/ffff/ffff\033\\\033[?62;22c", BackgroundLight, true},
+		{"\033]11;rgb:0000/0000/0000\a", BackgroundDark, true},
+		{"\033]11;rgb:fd/f6/e3\a", BackgroundLight, true},
+		{"\033]11;rgb:2/2/3\033\\", BackgroundDark, true},
+		{"\033]11;rgb:ffff/0000/0000\a", BackgroundDark, true},
+		{"\033[?1;2c", BackgroundUnknown, false},
+		{"\033]11;rgb:ffff/ffff\a", BackgroundUnknown, false},
+		{"\033]11;rgb:fffff/0/0\a", BackgroundUnknown, false},
+		{"\033]11;rgb:zz/00/00\a", BackgroundUnknown, false},
+		{"", BackgroundUnknown, false},
+	}
+	for _, tt := range tests {
+		if got, ok := parseOSC11([]byte(tt.reply)); got != tt.want || ok != tt.ok {
+			t.Errorf("parseOSC11(%q) = %v, %v, want %v, %v", tt.reply, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHasDeviceAttributes(t *testing.T) {
+	for reply, want := range map[string]bool{
+		"\033]11;rgb:0/0/0\a\033[?62;22c": true,
+		"\033[?1;2c":                      true,
+		"\033]11;rgb:0/0/0\a\033[?62;2":   false,
+		"\033]11;rgb:0/0/0\a":             false,
+	} {
+		if got := hasDeviceAttributes([]byte(reply)); got != want {
+			t.Errorf("hasDeviceAttributes(%q) = %v, want %v", reply, got, want)
+		}
+	}
+}
+
+func TestParseCOLORFGBG(t *testing.T) {
+	tests := []struct {
+		v    string
+		want Background
+		ok   bool
+	}{
+		{"15;0", BackgroundDark, true},
+		{"0;15", BackgroundLight, true},
+		{"0;7", BackgroundLight, true},
+		{"7;8", BackgroundDark, true},
+		{"0;default;15", BackgroundLight, true},
+		{"15;default;0", BackgroundDark, true},
+		{"", BackgroundUnknown, false},
+		{"15;default", BackgroundUnknown, false},
+		{"0;42", BackgroundUnknown, false},
+	}
+	for _, tt := range tests {
+		if got, ok := parseCOLORFGBG(tt.v); got != tt.want || ok != tt.ok {
+			t.Errorf("parseCOLORFGBG(%q) = %v, %v, want %v, %v", tt.v, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetectBackgroundFallback(t *testing.T) {
+	t.Setenv("COLORFGBG", "0;15")
+	if bg, ok := DetectBackground(&bytes.Buffer{}); bg != BackgroundLight || !ok {
+		t.Errorf("DetectBackground = %v, %v, want light from COLORFGBG", bg, ok)
+	}
+	t.Setenv("COLORFGBG", "")
+	if bg, ok := DetectBackground(&bytes.Buffer{}); bg != BackgroundUnknown || ok {
+		t.Errorf("DetectBackground without COLORFGBG = %v, %v, want unknown", bg, ok)
+	}
+}
+
+func TestBackgroundAuto(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	t.Setenv("COLORFGBG", "")
+	queries := 0
+	query := queryBackground
+	queryBackground = func() (Background, bool) {
+		queries++
+		return BackgroundLight, true
+	}
+	autoBackgroundOnce = sync.Once{}
+	defer func() {
+		queryBackground = query
+		autoBackgroundOnce = sync.Once{}
+	}()
+
+	opts := []Option{WithWriter(&bytes.Buffer{}), WithForceTTY(true), WithHideCursor(false)}
+	s := New(opts...)
+	s.Reset(opts...)
+	s.Start()
+	s.Stop()
+	if queries != 0 {
+		t.Fatalf("a spinner without BackgroundAuto queried the terminal %d times", queries)
+	}
+
+	s = New(append(opts, WithBackgroundHint(BackgroundAuto))...)
+	if queries != 0 {
+		t.Fatalf("New with BackgroundAuto queried the terminal")
+	}
+	s.Start()
+	s.Stop()
+	if queries != 1 {
+		t.Errorf("Start with BackgroundAuto queried the terminal %d times, want 1", queries)
+	}
+	if got := s.color(FrameInfo{}); got != Grey {
+		t.Errorf("color on a light background = %q, want Grey", got)
+	}
+}
@@ -0,0 +1,88 @@
+package spinner
+
+import (
+	"testing"
+	"time"
+)
+
+// tickConfigs are the spinner configurations the tick benchmarks and
+// allocation limits cover, from a static spinner that draws prerendered
+// lines to ones that render every frame anew.
+var tickConfigs = []struct {
+	name   string
+	opts   []Option
+	allocs float64 // most allocations a tick may make
+}{
+	{"static", []Option{WithFrames(Material), WithMessage("loading")}, 1},
+	{"static/prefix", []Option{WithFrames(Dots1), WithPrefix("["), WithSuffix("]"), WithMessage("loading")}, 1},
+	{"color-func", []Option{WithFrames(Dots1), WithMessage("loading"), WithColorFunc(GreyPulse(time.Millisecond))}, 7},
+	{"color-frame-func", []Option{WithFrames(Dots1), WithMessage("loading"), WithColorFrameFunc(LoopSyncedPulse(238, 255))}, 8},
+	{"interval-func", []Option{WithFrames(Dots1), WithMessage("loading"), WithIntervalFunc(SpeedupInterval(100*time.Millisecond, 20*time.Millisecond, time.Second))}, 1},
+	{"elapsed", []Option{WithFrames(Dots1), WithMessage("loading"), WithElapsed(true)}, 8},
+	{"progress", []Option{WithFrames(Dots1), WithMessage("loading"), WithProgressBar(20)}, 11},
+}
+
+// newTickSpinner returns a started spinner built from opts whose frames
+// are drawn only by calling tick, having drawn each frame once.
+func newTickSpinner(opts []Option) *Spinner {
+	s := New(append([]Option{WithWriter(&countingWriter{})}, opts...)...)
+	s.loop = manualLoop
+	s.Start()
+	if s.barWidth > 0 {
+		s.SetProgress(1, 3)
+	}
+	for range s.frames {
+		s.tick()
+	}
+	return s
+}
+
+func BenchmarkTick(b *testing.B) {
+	b.Setenv("TERM", "xterm")
+	for _, bc := range tickConfigs {
+		b.Run(bc.name, func(b *testing.B) {
+			s := newTickSpinner(bc.opts)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.tick()
+			}
+			b.StopTimer()
+			s.Stop()
+		})
+	}
+}
+
+func TestTickAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates on channel operations")
+	}
+	t.Setenv("TERM", "xterm")
+	for _, tc := range tickConfigs {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTickSpinner(tc.opts)
+			defer s.Stop()
+			if got := testing.AllocsPerRun(100, func() { s.tick() }); got > tc.allocs {
+				t.Errorf("tick made %v allocations, want at most %v", got, tc.allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkDisplayWidth(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		text string
+	}{
+		{"ascii", "- loading the index"},
+		{"ansi", "\033[38;5;208m⠋\033[0m loading the index"},
+		{"emoji", "❤️ 🌍 loading the index"},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = displayWidth(bc.text)
+			}
+		})
+	}
+}
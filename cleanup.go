@@ -0,0 +1,56 @@
+package spinner
+
+import "sync"
+
+// running lists the spinners that are running, in the order they started,
+// for the package-level Print functions and Cleanup. Start adds a spinner
+// and it is removed as soon as it stops, so the list never keeps a stopped
+// spinner alive.
+var running = struct {
+	sync.Mutex
+	list []*Spinner
+}{}
+
+// track adds s to the running spinners.
+func track(s *Spinner) {
+	running.Lock()
+	defer running.Unlock()
+	running.list = append(running.list, s)
+}
+
+// untrack removes s from the running spinners.
+func untrack(s *Spinner) {
+	running.Lock()
+	defer running.Unlock()
+	for i, r := range running.list {
+		if r == s {
+			running.list = append(running.list[:i], running.list[i+1:]...)
+			return
+		}
+	}
+}
+
+// current returns the spinner that started most recently of those still
+// running, or nil if none is.
+func current() *Spinner {
+	running.Lock()
+	defer running.Unlock()
+	if len(running.list) == 0 {
+		return nil
+	}
+	return running.list[len(running.list)-1]
+}
+
+// Cleanup stops every running spinner at once, without waiting for outros,
+// clearing their lines and showing the cursor again. It returns once all of
+// them have stopped. Call it from a deferred function in main, or just
+// before os.Exit, which skips deferred calls, so that a spinner left
+// running does not leave the terminal with a hidden cursor.
+func Cleanup() {
+	running.Lock()
+	list := append([]*Spinner(nil), running.list...)
+	running.Unlock()
+	for i := len(list) - 1; i >= 0; i-- {
+		list[i].StopNow()
+	}
+}
@@ -0,0 +1,62 @@
+package spinner
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// brokenWriter fails every write.
+type brokenWriter struct{}
+
+func (brokenWriter) Write([]byte) (int, error) { return 0, errors.New("broken") }
+
+// tracked returns how many spinners the registry holds.
+func tracked() int {
+	running.Lock()
+	defer running.Unlock()
+	return len(running.list)
+}
+
+func TestRegistryDoesNotLeak(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	base := tracked()
+	start := func(opts ...Option) *Spinner {
+		s := New(append([]Option{WithWriter(io.Discard)}, opts...)...)
+		s.loop = manualLoop
+		s.Start()
+		return s
+	}
+	start().Stop()
+	start().Success("ok")
+	start().StopNow()
+	start().Reset()
+	start(WithWriter(brokenWriter{})).tick()
+	t.Setenv("TERM", "dumb")
+	start().Stop()
+	if n := tracked() - base; n != 0 {
+		t.Errorf("registry holds %d stopped spinners", n)
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var bufs [3]bytes.Buffer
+	for i := range bufs {
+		s := New(WithWriter(&bufs[i]), WithForceTTY(true), WithColorMode(ColorNever), WithOutroFrames([]string{"x"}))
+		s.loop = manualLoop
+		s.Start()
+		s.tick()
+	}
+	Cleanup()
+	if n := tracked(); n != 0 {
+		t.Errorf("%d spinners still running after Cleanup", n)
+	}
+	for i := range bufs {
+		if out := bufs[i].String(); !strings.HasSuffix(out, "\r \r"+ShowCursor) {
+			t.Errorf("spinner %d wrote %q, want the line cleared and the cursor shown", i, out)
+		}
+	}
+}
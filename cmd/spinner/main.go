@@ -0,0 +1,72 @@
+// Command spinner previews the spinner styles in this module.
+//
+// With no flags it shows every registered style in turn. When standard error
+// is not a terminal it lists the style names instead of animating.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func main() {
+	var (
+		style    spinner.StyleFlag
+		color    spinner.ColorFlag
+		interval spinner.IntervalFlag
+		message  = flag.String("message", "", "message shown next to the spinner (default: the style name)")
+		duration = flag.Duration("duration", 2*time.Second, "how long to show each style")
+		list     = flag.Bool("list", false, "list the available styles and exit")
+	)
+	flag.Var(&style, "style", "show only this style (see -list)")
+	flag.Var(&color, "color", "frame color: a name such as red, a 256-color number, or #rrggbb")
+	flag.Var(&interval, "interval", "frame interval (default: the style's own)")
+	flag.Parse()
+
+	if *list || !spinner.New().Config().TTY {
+		for _, name := range spinner.StyleNames() {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	var styles []spinner.Style
+	if style.Style.Name != "" {
+		styles = []spinner.Style{style.Style}
+	} else {
+		for _, name := range spinner.StyleNames() {
+			st, _ := spinner.LookupStyle(name)
+			styles = append(styles, st)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	for _, st := range styles {
+		opts := []spinner.Option{spinner.WithStyle(st), spinner.WithMessage(st.Name)}
+		if *message != "" {
+			opts = append(opts, spinner.WithMessage(*message))
+		}
+		if interval.Interval > 0 {
+			opts = append(opts, spinner.WithInterval(interval.Interval))
+		}
+		if color.Color != "" {
+			opts = append(opts, spinner.WithColor(color.Color))
+		}
+		s := spinner.New(opts...)
+		s.Start()
+		select {
+		case <-ctx.Done():
+			s.Stop()
+			return
+		case <-time.After(*duration):
+			s.Stop()
+		}
+	}
+}
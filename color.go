@@ -0,0 +1,164 @@
+package spinner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Colors maps the names of the package's color constants, in lower case,
+// to their escape sequences, so that a color can be chosen by name, as in
+// a --color=red flag. It should not be changed once the program has
+// started spinners or parsing flags.
+var Colors = map[string]string{
+	"black":  Black,
+	"green":  Green,
+	"olive":  Olive,
+	"navy":   Navy,
+	"teal":   Teal,
+	"silver": Silver,
+	"grey":   Grey,
+	"red":    Red,
+	"lime":   Lime,
+	"yellow": Yellow,
+	"blue":   Blue,
+	"aqua":   Aqua,
+	"white":  White,
+}
+
+// ColorByName returns the escape sequence of the color in Colors named
+// name, which is matched case-insensitively.
+func ColorByName(name string) (string, bool) {
+	color, ok := Colors[strings.ToLower(name)]
+	return color, ok
+}
+
+// ColorNames returns the names in Colors in sorted order, for help output.
+func ColorNames() []string {
+	names := make([]string, 0, len(Colors))
+	for name := range Colors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ColorMode controls whether the spinner emits color escape sequences.
+type ColorMode int
+
+const (
+	// ColorAuto resolves the mode from the environment and the writer.
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+func (m ColorMode) String() string {
+	switch m {
+	case ColorAuto:
+		return "auto"
+	case ColorAlways:
+		return "always"
+	case ColorNever:
+		return "never"
+	}
+	return fmt.Sprintf("ColorMode(%d)", int(m))
+}
+
+// WithColorMode overrides the color mode resolved by ResolveColorMode.
+func WithColorMode(mode ColorMode) Option {
+	return func(s *Spinner) {
+		s.colorMode = mode
+	}
+}
+
+// ResolveColorMode reports whether output to w should be colored, returning
+// ColorAlways or ColorNever. The first rule that applies wins:
+//
+//   - NO_COLOR set to a non-empty value disables color.
+//   - CLICOLOR_FORCE set to a non-empty value other than "0" enables color.
+//   - FORCE_COLOR enables color when set, unless it is "0" or "false", which
+//     disables it.
+//   - Otherwise color is enabled only when w is a terminal.
+//
+// A mode passed to WithColorMode takes precedence over all of these.
+func ResolveColorMode(w io.Writer) ColorMode {
+	return resolveColorMode(isTerminal(w))
+}
+
+// resolveColorMode is ResolveColorMode for a writer that tty says is a
+// terminal or not.
+func resolveColorMode(tty bool) ColorMode {
+	if os.Getenv("NO_COLOR") != "" {
+		return ColorNever
+	}
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return ColorAlways
+	}
+	if v, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		if v == "0" || v == "false" {
+			return ColorNever
+		}
+		return ColorAlways
+	}
+	if tty {
+		return ColorAlways
+	}
+	return ColorNever
+}
+
+// Background describes the terminal's background color.
+type Background int
+
+const (
+	BackgroundUnknown Background = iota
+	BackgroundLight
+	BackgroundDark
+	// BackgroundAuto, as a hint, has the spinner ask the terminal.
+	BackgroundAuto
+)
+
+func (b Background) String() string {
+	switch b {
+	case BackgroundUnknown:
+		return "unknown"
+	case BackgroundLight:
+		return "light"
+	case BackgroundDark:
+		return "dark"
+	case BackgroundAuto:
+		return "auto"
+	}
+	return fmt.Sprintf("Background(%d)", int(b))
+}
+
+// WithBackgroundHint tells the spinner whether the terminal has a light or
+// dark background. Unless a color is set explicitly, frames are then drawn
+// in a color that contrasts with it: dark Grey on a light background and
+// White on a dark one. Without a hint, a spinner writing to a terminal goes
+// by the COLORFGBG environment variable, and the default is White if that
+// is not set. BackgroundAuto has the first spinner to start use
+// DetectBackground, which queries the terminal; New never does.
+func WithBackgroundHint(bg Background) Option {
+	return func(s *Spinner) {
+		s.background = bg
+	}
+}
+
+// defaultColor returns the frame color used when none is set.
+func defaultColor(bg Background) string {
+	if bg == BackgroundLight {
+		return Grey
+	}
+	return White
+}
+
+// paint wraps text in color and Reset when the spinner uses color.
+func (s *Spinner) paint(color, text string) string {
+	if s.colorMode == ColorNever {
+		return text
+	}
+	return sgrSpan(color) + text + Reset
+}
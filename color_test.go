@@ -0,0 +1,126 @@
+package spinner_test
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+// setenv sets or, for a nil value, unsets key for the duration of the test.
+func setenv(t *testing.T, key string, value *string) {
+	t.Helper()
+	t.Setenv(key, "")
+	if value == nil {
+		os.Unsetenv(key)
+		return
+	}
+	os.Setenv(key, *value)
+}
+
+func TestResolveColorMode(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		noColor, clicolorForce, forceColor *string
+		want                               spinner.ColorMode
+	}{
+		{nil, nil, nil, spinner.ColorNever},
+		{str(""), nil, nil, spinner.ColorNever},
+		{str("1"), nil, nil, spinner.ColorNever},
+		{str("1"), str("1"), nil, spinner.ColorNever},
+		{str("1"), nil, str("1"), spinner.ColorNever},
+		{nil, str("1"), nil, spinner.ColorAlways},
+		{nil, str("0"), nil, spinner.ColorNever},
+		{str(""), str("1"), nil, spinner.ColorAlways},
+		{nil, nil, str(""), spinner.ColorAlways},
+		{nil, nil, str("3"), spinner.ColorAlways},
+		{nil, nil, str("true"), spinner.ColorAlways},
+		{nil, nil, str("0"), spinner.ColorNever},
+		{nil, nil, str("false"), spinner.ColorNever},
+		{nil, str("1"), str("0"), spinner.ColorAlways},
+		{nil, str("0"), str("1"), spinner.ColorAlways},
+	}
+	show := func(v *string) string {
+		if v == nil {
+			return "unset"
+		}
+		return strconv.Quote(*v)
+	}
+	for _, tt := range tests {
+		name := "NO_COLOR=" + show(tt.noColor) + ",CLICOLOR_FORCE=" + show(tt.clicolorForce) + ",FORCE_COLOR=" + show(tt.forceColor)
+		t.Run(name, func(t *testing.T) {
+			setenv(t, "NO_COLOR", tt.noColor)
+			setenv(t, "CLICOLOR_FORCE", tt.clicolorForce)
+			setenv(t, "FORCE_COLOR", tt.forceColor)
+			if got := spinner.ResolveColorMode(&bytes.Buffer{}); got != tt.want {
+				t.Errorf("ResolveColorMode = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithColorModeOverridesEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if got := spinner.New(spinner.WithColorMode(spinner.ColorAlways)).Config().ColorMode; got != spinner.ColorAlways {
+		t.Errorf("ColorMode = %v with explicit ColorAlways and NO_COLOR", got)
+	}
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+	if got := spinner.New(spinner.WithColorMode(spinner.ColorNever)).Config().ColorMode; got != spinner.ColorNever {
+		t.Errorf("ColorMode = %v with explicit ColorNever and FORCE_COLOR", got)
+	}
+}
+
+func TestColorNeverOutput(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(
+		spinner.WithWriter(&buf),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithHideCursor(false),
+		spinner.WithFrames([]string{"-"}),
+	)
+	s.Start()
+	time.Sleep(50 * time.Millisecond)
+	s.Success("ok")
+	if out := buf.String(); strings.Contains(out, "\033[") {
+		t.Errorf("output %q contains escape sequences", out)
+	}
+}
+
+func TestBackgroundHint(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []spinner.Option
+		want string
+	}{
+		{"none", nil, spinner.White},
+		{"light", []spinner.Option{spinner.WithBackgroundHint(spinner.BackgroundLight)}, spinner.Grey},
+		{"dark", []spinner.Option{spinner.WithBackgroundHint(spinner.BackgroundDark)}, spinner.White},
+		{"explicit", []spinner.Option{spinner.WithBackgroundHint(spinner.BackgroundLight), spinner.WithColor(spinner.Red)}, spinner.Red},
+		{"COLORFGBG with forced tty", []spinner.Option{spinner.WithForceTTY(true), spinner.WithHideCursor(false)}, spinner.Grey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERM", "xterm")
+			t.Setenv("COLORFGBG", "0;15")
+			var lines []string
+			opts := append([]spinner.Option{
+				spinner.WithWriter(&bytes.Buffer{}),
+				spinner.WithColorMode(spinner.ColorAlways),
+				spinner.WithInterval(time.Millisecond),
+				spinner.WithRecorder(&lines),
+			}, tt.opts...)
+			s := spinner.New(opts...)
+			s.Start()
+			time.Sleep(20 * time.Millisecond)
+			s.Stop()
+			if len(lines) == 0 || !strings.HasPrefix(lines[0], tt.want) {
+				t.Errorf("lines = %q, want frames colored %q", lines, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrConflictingOptions is reported by NewWithError when two mutually
+// exclusive options were supplied.
+var ErrConflictingOptions = errors.New("spinner: conflicting options")
+
+// NewWithError is like New but reports option combinations that New
+// silently resolves by letting the last option win, and a nil writer, which
+// makes New panic.
+func NewWithError(opts ...Option) (*Spinner, error) {
+	s := newSpinner(opts)
+	if err := errors.Join(s.optErrs...); err != nil {
+		return nil, err
+	}
+	if s.autoStart {
+		s.Start()
+	}
+	return s, nil
+}
+
+// setSource records that opt set a setting from src. name holds the option
+// that last set the setting; a different option setting it again is a
+// conflict.
+func (s *Spinner) setSource(field *Source, name *string, src Source, opt string) {
+	if *name != "" && *name != opt {
+		s.optErrs = append(s.optErrs, fmt.Errorf("%w: %s and %s", ErrConflictingOptions, *name, opt))
+	}
+	*field, *name = src, opt
+}
+
+// Source describes where a setting's value comes from.
+type Source int
+
+const (
+	SourceDefault Source = iota
+	SourceFixed
+	SourceFunc
+)
+
+func (src Source) String() string {
+	switch src {
+	case SourceDefault:
+		return "default"
+	case SourceFixed:
+		return "fixed"
+	case SourceFunc:
+		return "func"
+	}
+	return fmt.Sprintf("Source(%d)", int(src))
+}
+
+// WriterKind classifies the writer a spinner renders to.
+type WriterKind int
+
+const (
+	WriterOther WriterKind = iota
+	WriterStdout
+	WriterStderr
+	WriterFile
+)
+
+func (k WriterKind) String() string {
+	switch k {
+	case WriterOther:
+		return "other"
+	case WriterStdout:
+		return "stdout"
+	case WriterStderr:
+		return "stderr"
+	case WriterFile:
+		return "file"
+	}
+	return fmt.Sprintf("WriterKind(%d)", int(k))
+}
+
+func writerKind(w io.Writer) WriterKind {
+	switch w {
+	case os.Stdout:
+		return WriterStdout
+	case os.Stderr:
+		return WriterStderr
+	}
+	if _, ok := w.(*os.File); ok {
+		return WriterFile
+	}
+	return WriterOther
+}
+
+// Config is a snapshot of the resolved settings of a Spinner.
+type Config struct {
+	Frames         int
+	Interval       time.Duration // zero when IntervalSource is SourceFunc
+	IntervalSource Source
+	ColorSource    Source
+	ColorMode      ColorMode
+	HideCursor     bool
+	ClearOnStop    bool
+	StopNewline    bool
+	TTY            bool
+	Writer         WriterKind
+}
+
+// Config returns the settings the spinner is currently using.
+func (s *Spinner) Config() Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Config{
+		Frames:         len(s.frames),
+		Interval:       s.fixedInterval,
+		IntervalSource: s.intervalSource,
+		ColorSource:    s.colorSource,
+		ColorMode:      s.colorMode,
+		HideCursor:     s.cursorMode == CursorHide && s.term.ansi,
+		ClearOnStop:    s.clearOnStop,
+		StopNewline:    s.stopNewline,
+		TTY:            s.tty,
+		Writer:         writerKind(s.writer),
+	}
+}
@@ -0,0 +1,99 @@
+package spinner_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestNewWithErrorConflicts(t *testing.T) {
+	interval := func() time.Duration { return time.Millisecond }
+	color := func() string { return spinner.Red }
+	tests := []struct {
+		name     string
+		opts     []spinner.Option
+		conflict bool
+	}{
+		{"none", nil, false},
+		{"interval twice", []spinner.Option{spinner.WithInterval(time.Second), spinner.WithInterval(time.Millisecond)}, false},
+		{"interval then func", []spinner.Option{spinner.WithInterval(time.Second), spinner.WithIntervalFunc(interval)}, true},
+		{"func then interval", []spinner.Option{spinner.WithIntervalFunc(interval), spinner.WithInterval(time.Second)}, true},
+		{"color and func", []spinner.Option{spinner.WithColor(spinner.Red), spinner.WithColorFunc(color)}, true},
+		{"func and frame func", []spinner.Option{spinner.WithColorFunc(color), spinner.WithColorFrameFunc(spinner.LoopSyncedPulse(0, 1))}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := spinner.NewWithError(tt.opts...)
+			if got := errors.Is(err, spinner.ErrConflictingOptions); got != tt.conflict {
+				t.Fatalf("NewWithError error = %v, want conflict %v", err, tt.conflict)
+			}
+			if (s == nil) != tt.conflict {
+				t.Errorf("NewWithError spinner = %v, want nil only on error", s)
+			}
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	c := spinner.New().Config()
+	want := spinner.Config{
+		Frames:         len(spinner.Dots1),
+		Interval:       60 * time.Millisecond,
+		IntervalSource: spinner.SourceDefault,
+		ColorSource:    spinner.SourceDefault,
+		ColorMode:      c.ColorMode,
+		HideCursor:     c.TTY,
+		ClearOnStop:    true,
+		TTY:            c.TTY,
+		Writer:         spinner.WriterStderr,
+	}
+	if c != want {
+		t.Errorf("default Config() = %+v, want %+v", c, want)
+	}
+
+	c = spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithFrames(spinner.Line),
+		spinner.WithIntervalFunc(func() time.Duration { return time.Second }),
+		spinner.WithColor(spinner.Red),
+		spinner.WithColorMode(spinner.ColorNever),
+	).Config()
+	want = spinner.Config{
+		Frames:         len(spinner.Line),
+		IntervalSource: spinner.SourceFunc,
+		ColorSource:    spinner.SourceFixed,
+		ColorMode:      spinner.ColorNever,
+		ClearOnStop:    true,
+		Writer:         spinner.WriterOther,
+	}
+	if c != want {
+		t.Errorf("Config() = %+v, want %+v", c, want)
+	}
+
+	if got := spinner.New(spinner.WithWriter(os.Stdout)).Config().Writer; got != spinner.WriterStdout {
+		t.Errorf("Config().Writer = %v for os.Stdout", got)
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	theme := spinner.WithOptions(
+		spinner.WithFrames([]string{"a", "b", "c"}),
+		spinner.WithInterval(time.Second),
+		spinner.WithHideCursor(false),
+	)
+	cfg := spinner.New(
+		spinner.WithForceTTY(true),
+		spinner.WithInterval(time.Minute),
+		theme,
+		spinner.WithHideCursor(true),
+	).Config()
+	if cfg.Frames != 3 || cfg.Interval != time.Second || !cfg.HideCursor {
+		t.Errorf("Config = %+v, want 3 frames and 1s from the bundle, HideCursor from after it", cfg)
+	}
+}
@@ -0,0 +1,96 @@
+package spinner
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// countdown is a Countdown in progress.
+type countdown struct {
+	end     time.Time
+	format  string
+	message string // the message the countdown replaced
+	done    chan struct{}
+	elapsed bool // set before done is closed
+}
+
+// Countdown shows the time left until d has passed in place of the message,
+// formatted by format, such as "retrying in %s", with the time rounded up
+// to whole seconds. It waits until d has passed and then puts the message
+// back and returns true, which makes it suit the waits of a retry loop with
+// backoff. The countdown is drawn by the spinner's usual ticks.
+//
+// It returns false as soon as the countdown is cut short, either by the
+// spinner stopping or by UpdateMessage, whose message then replaces the
+// countdown. Starting another countdown cuts short the one before. A
+// spinner that is not drawing, because it is idle, disabled or on a dumb
+// terminal, shows nothing, but Countdown still waits out d.
+func (s *Spinner) Countdown(d time.Duration, format string) bool {
+	s.mu.Lock()
+	if s.state != stateRunning || s.plain {
+		done := s.done
+		if s.state != stateRunning {
+			done = nil
+		}
+		s.mu.Unlock()
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+			return true
+		case <-done:
+			return false
+		}
+	}
+	c := s.startCountdown(s.now().Add(d), format)
+	s.mu.Unlock()
+	<-c.done
+	return c.elapsed
+}
+
+// startCountdown cuts short any countdown in progress and starts one that
+// ends at end. The caller must hold mu, and the spinner must be drawing.
+func (s *Spinner) startCountdown(end time.Time, format string) *countdown {
+	s.endCountdown(false)
+	s.applyMessage()
+	c := &countdown{
+		end:     end,
+		format:  format,
+		message: s.message,
+		done:    make(chan struct{}),
+	}
+	s.countdown = c
+	s.updateCountdown()
+	return c
+}
+
+// updateCountdown shows the time left in the countdown, or ends it if its
+// time is up or a new message is waiting. The caller must hold mu.
+func (s *Spinner) updateCountdown() {
+	c := s.countdown
+	left := c.end.Sub(s.now())
+	switch {
+	case s.pendingMessage.Load() != nil:
+		s.endCountdown(false)
+	case left <= 0:
+		s.endCountdown(true)
+	default:
+		secs := time.Duration(math.Ceil(left.Seconds())) * time.Second
+		s.message = s.sanitize(fmt.Sprintf(c.format, secs))
+	}
+}
+
+// endCountdown ends the countdown in progress, if there is one, and puts
+// back the message it replaced. elapsed is what Countdown returns. The
+// caller must hold mu.
+func (s *Spinner) endCountdown(elapsed bool) {
+	c := s.countdown
+	if c == nil {
+		return
+	}
+	s.countdown = nil
+	s.message = c.message
+	c.elapsed = elapsed
+	close(c.done)
+}
@@ -0,0 +1,86 @@
+package spinner
+
+import (
+	"io"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// startCountdown calls s.Countdown on another goroutine and returns once
+// the countdown is showing. The channel receives its result.
+func startCountdown(s *Spinner, d time.Duration) <-chan bool {
+	result := make(chan bool, 1)
+	go func() { result <- s.Countdown(d, "retrying in %s") }()
+	for {
+		s.mu.Lock()
+		c := s.countdown
+		s.mu.Unlock()
+		if c != nil {
+			return result
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	tests := []struct {
+		name    string
+		cut     func(*Spinner) // called after the first tick, if set
+		want    []string
+		elapsed bool
+	}{
+		{
+			name:    "elapsed",
+			want:    []string{"- retrying in 3s", "- retrying in 2s", "- retrying in 1s", "- working"},
+			elapsed: true,
+		},
+		{
+			name: "new message",
+			cut:  func(s *Spinner) { s.UpdateMessage("attempt 2") },
+			want: []string{"- retrying in 3s", "- attempt 2", "- attempt 2", "- attempt 2"},
+		},
+		{
+			name: "stop",
+			cut:  func(s *Spinner) { s.Stop() },
+			want: []string{"- retrying in 3s"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{time.Unix(0, 0)}
+			var lines []string
+			s := New(WithWriter(io.Discard), WithFrames([]string{"-"}), WithMessage("working"), WithRecorder(&lines))
+			s.now, s.loop = clock.now, manualLoop
+			s.Start()
+			result := startCountdown(s, 2500*time.Millisecond)
+			for i := 0; i < 4; i++ {
+				s.tick()
+				if i == 0 && tt.cut != nil {
+					tt.cut(s)
+				}
+				clock.t = clock.t.Add(time.Second)
+			}
+			if got := <-result; got != tt.elapsed {
+				t.Errorf("Countdown returned %v, want %v", got, tt.elapsed)
+			}
+			s.Stop()
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("lines = %q, want %q", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountdownIdle(t *testing.T) {
+	s := New(WithWriter(io.Discard))
+	start := time.Now()
+	if !s.Countdown(20*time.Millisecond, "%s") {
+		t.Error("Countdown on an idle spinner returned false")
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("Countdown on an idle spinner returned after %v", d)
+	}
+}
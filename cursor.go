@@ -0,0 +1,111 @@
+package spinner
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// CursorMode says what the spinner does with the cursor while it draws.
+type CursorMode int
+
+const (
+	// CursorHide hides the cursor while the spinner runs and draws at the
+	// start of the cursor's line. It is the default.
+	CursorHide CursorMode = iota
+	// CursorSaveRestore leaves the cursor visible and where it is: every
+	// redraw saves the cursor, draws from its position and restores it, so
+	// the spinner can follow a prompt on the same line without moving the
+	// user's cursor.
+	CursorSaveRestore
+	// CursorNone leaves the cursor visible and draws as CursorHide does.
+	CursorNone
+)
+
+func (m CursorMode) String() string {
+	switch m {
+	case CursorHide:
+		return "hide"
+	case CursorSaveRestore:
+		return "save-restore"
+	case CursorNone:
+		return "none"
+	}
+	return fmt.Sprintf("CursorMode(%d)", int(m))
+}
+
+// WithCursorMode sets what the spinner does with the cursor. The default is
+// CursorHide.
+func WithCursorMode(mode CursorMode) Option {
+	return func(s *Spinner) {
+		s.cursorMode = mode
+	}
+}
+
+// savesCursor reports whether the spinner draws inline between a saved and
+// restored cursor.
+func (s *Spinner) savesCursor() bool {
+	return s.cursorMode == CursorSaveRestore && s.row == 0
+}
+
+var cursorHides = struct {
+	sync.Mutex
+	count map[io.Writer]int
+}{count: make(map[io.Writer]int)}
+
+// AcquireCursorHide hides the cursor on w unless it is already hidden by an
+// earlier call, and counts the call. Together with ReleaseCursorHide it lets
+// several spinners and other components share a writer without one showing
+// the cursor while another still wants it hidden. Spinners hide the cursor
+// this way, so a program that keeps its own hold on the cursor stops them
+// from showing it on Stop.
+func AcquireCursorHide(w io.Writer) error {
+	if !writerComparable(w) {
+		_, err := io.WriteString(w, HideCursor)
+		return err
+	}
+	cursorHides.Lock()
+	defer cursorHides.Unlock()
+	cursorHides.count[w]++
+	if cursorHides.count[w] > 1 {
+		return nil
+	}
+	_, err := io.WriteString(w, HideCursor)
+	return err
+}
+
+// ReleaseCursorHide undoes one AcquireCursorHide on w, showing the cursor
+// again once every call has been released.
+func ReleaseCursorHide(w io.Writer) error {
+	if !releaseCursorHide(w) {
+		return nil
+	}
+	_, err := io.WriteString(w, ShowCursor)
+	return err
+}
+
+// releaseCursorHide drops a hold on w's cursor and reports whether it was
+// the last one.
+func releaseCursorHide(w io.Writer) bool {
+	if !writerComparable(w) {
+		return true
+	}
+	cursorHides.Lock()
+	defer cursorHides.Unlock()
+	n, ok := cursorHides.count[w]
+	switch {
+	case !ok:
+		return false
+	case n > 1:
+		cursorHides.count[w] = n - 1
+		return false
+	}
+	delete(cursorHides.count, w)
+	return true
+}
+
+// writerComparable reports whether w can be used as a map key.
+func writerComparable(w io.Writer) bool {
+	return w != nil && reflect.TypeOf(w).Comparable()
+}
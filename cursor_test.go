@@ -0,0 +1,82 @@
+package spinner_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestCursorHideRefCount(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var buf bytes.Buffer
+	shown := func() int { return strings.Count(buf.String(), spinner.ShowCursor) }
+	hidden := func() int { return strings.Count(buf.String(), spinner.HideCursor) }
+
+	// The program holds the cursor hidden for its whole UI.
+	spinner.AcquireCursorHide(&buf)
+	a := spinner.New(spinner.WithWriter(&buf), spinner.WithForceTTY(true), spinner.WithInterval(time.Millisecond))
+	b := spinner.New(spinner.WithWriter(&buf), spinner.WithForceTTY(true), spinner.WithInterval(time.Millisecond))
+	a.Start()
+	b.Start()
+	time.Sleep(5 * time.Millisecond)
+	a.Stop()
+	b.Stop()
+	if hidden() != 1 || shown() != 0 {
+		t.Errorf("cursor hidden %d and shown %d times while the program holds it", hidden(), shown())
+	}
+	spinner.ReleaseCursorHide(&buf)
+	if shown() != 1 {
+		t.Errorf("cursor shown %d times after the last release, want 1", shown())
+	}
+
+	// Without other holders a spinner hides and shows the cursor itself.
+	buf.Reset()
+	a.Start()
+	a.Stop()
+	if !strings.HasPrefix(buf.String(), spinner.HideCursor) || !strings.HasSuffix(buf.String(), spinner.ShowCursor) {
+		t.Errorf("lone spinner output %q", buf.String())
+	}
+	if err := spinner.ReleaseCursorHide(&buf); err != nil || shown() != 1 {
+		t.Errorf("unmatched release wrote %q", buf.String())
+	}
+}
+
+func TestCursorMode(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	tests := []struct {
+		mode       spinner.CursorMode
+		draw, stop string
+		hide       bool
+	}{
+		{spinner.CursorHide, "\r- syncing", "\r         \r", true},
+		{spinner.CursorSaveRestore, "\033[s- syncing\033[u", "\033[s         \033[u", false},
+		{spinner.CursorNone, "\r- syncing", "\r         \r", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			s := spinner.New(
+				spinner.WithWriter(&buf),
+				spinner.WithForceTTY(true),
+				spinner.WithCursorMode(tt.mode),
+				spinner.WithColorMode(spinner.ColorNever),
+				spinner.WithFrames([]string{"-"}),
+				spinner.WithMessage("syncing"),
+			)
+			s.Start()
+			time.Sleep(10 * time.Millisecond)
+			s.Stop()
+
+			out := strings.TrimPrefix(strings.TrimSuffix(buf.String(), spinner.ShowCursor), spinner.HideCursor)
+			if hid := out != buf.String(); hid != tt.hide {
+				t.Errorf("output %q: hid the cursor = %v, want %v", buf.String(), hid, tt.hide)
+			}
+			if !strings.HasPrefix(out, tt.draw) || !strings.HasSuffix(out, tt.stop) {
+				t.Errorf("output %q, want it to start with %q and end with %q", out, tt.draw, tt.stop)
+			}
+		})
+	}
+}
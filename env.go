@@ -0,0 +1,91 @@
+package spinner
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// WithEnvOverrides lets the environment override the spinner's appearance,
+// so that the users of a program can restyle every spinner in it without
+// code changes. The variables are read when the spinner is configured,
+// after all other options, so they take precedence over them:
+//
+//   - SPINNER_STYLE selects a registered style by name, with its interval;
+//   - SPINNER_INTERVAL sets the frame interval as a Go duration, such as
+//     "100ms";
+//   - SPINNER_COLOR sets the frame color as a name, a 256-color number
+//     written plainly or as "256:n", or "#rrggbb";
+//   - SPINNER_DISABLED, if true, turns the spinner off: Start does nothing,
+//     while Success, Fail and Stopf still print their final line.
+//
+// Values are parsed exactly as StyleFlag, IntervalFlag and ColorFlag parse
+// command-line flags. Unset or empty variables are ignored, and so are
+// invalid ones, so that a typo in the environment never stops a program
+// from building its spinner. EnvErrors reports them.
+func WithEnvOverrides() Option {
+	return func(s *Spinner) {
+		s.envOverrides = true
+	}
+}
+
+// EnvErrors returns the problems with the WithEnvOverrides variables found
+// when the spinner was last configured, joined into one error, or nil if
+// there were none.
+func (s *Spinner) EnvErrors() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.envErrs...)
+}
+
+// applyEnv applies the WithEnvOverrides variables.
+func (s *Spinner) applyEnv() {
+	var style StyleFlag
+	if s.lookupEnv("SPINNER_STYLE", &style) {
+		s.frames = style.Style.Frames
+		if d := style.Style.Interval; d > 0 {
+			s.envInterval("SPINNER_STYLE", d)
+		}
+	}
+	var interval IntervalFlag
+	if s.lookupEnv("SPINNER_INTERVAL", &interval) {
+		s.envInterval("SPINNER_INTERVAL", interval.Interval)
+	}
+	var color ColorFlag
+	if s.lookupEnv("SPINNER_COLOR", &color) {
+		s.colorSource, s.colorOpt = SourceFixed, "SPINNER_COLOR"
+		s.color = func(FrameInfo) string { return color.Color }
+	}
+	if v := os.Getenv("SPINNER_DISABLED"); v != "" {
+		disabled, err := strconv.ParseBool(v)
+		if err != nil {
+			s.envErrs = append(s.envErrs, fmt.Errorf("spinner: SPINNER_DISABLED: invalid boolean %q", v))
+		}
+		s.disabled = disabled
+	}
+}
+
+// lookupEnv sets v from the environment variable name and reports whether
+// it was set to a valid value.
+func (s *Spinner) lookupEnv(name string, v flag.Value) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return false
+	}
+	if err := v.Set(value); err != nil {
+		s.envErrs = append(s.envErrs, fmt.Errorf("spinner: %s: %w", name, err))
+		return false
+	}
+	return true
+}
+
+// envInterval sets a fixed interval taken from the environment variable
+// name.
+func (s *Spinner) envInterval(name string, d time.Duration) {
+	s.intervalSource, s.intervalOpt = SourceFixed, name
+	s.fixedInterval = d
+	s.interval = func() time.Duration { return d }
+}
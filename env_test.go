@@ -0,0 +1,85 @@
+package spinner_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	t.Setenv("SPINNER_STYLE", "line")
+	t.Setenv("SPINNER_COLOR", "#102030")
+	opts := []spinner.Option{spinner.WithFrames(spinner.Moon), spinner.WithColor(spinner.Red), spinner.WithColorMode(spinner.ColorAlways)}
+
+	c := spinner.New(opts...).Config()
+	if c.Frames != len(spinner.Moon) || c.IntervalSource != spinner.SourceDefault {
+		t.Errorf("without WithEnvOverrides: Config() = %+v", c)
+	}
+
+	var lines []string
+	s, err := spinner.NewWithError(append(opts, spinner.WithEnvOverrides(), spinner.WithWriter(&bytes.Buffer{}), spinner.WithRecorder(&lines))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.EnvErrors(); err != nil {
+		t.Errorf("EnvErrors = %v for valid variables", err)
+	}
+	if c := s.Config(); c.Frames != len(spinner.Line) || c.Interval != 130*time.Millisecond {
+		t.Errorf("SPINNER_STYLE=line: Config() = %+v", c)
+	}
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+	if len(lines) == 0 || lines[0] != "\033[38;2;16;32;48m-"+spinner.Reset {
+		t.Errorf("SPINNER_COLOR=#102030: lines = %q", lines)
+	}
+
+	t.Setenv("SPINNER_INTERVAL", "25ms")
+	if c := spinner.New(spinner.WithInterval(time.Second), spinner.WithEnvOverrides()).Config(); c.Interval != 25*time.Millisecond {
+		t.Errorf("SPINNER_INTERVAL=25ms over SPINNER_STYLE and WithInterval: Interval = %v", c.Interval)
+	}
+}
+
+func TestEnvOverridesInvalid(t *testing.T) {
+	t.Setenv("SPINNER_STYLE", "nope")
+	t.Setenv("SPINNER_INTERVAL", "-5ms")
+	t.Setenv("SPINNER_COLOR", "mauve")
+	t.Setenv("SPINNER_DISABLED", "maybe")
+	opts := []spinner.Option{spinner.WithFrames(spinner.Moon), spinner.WithInterval(time.Second), spinner.WithEnvOverrides()}
+
+	c := spinner.New(opts...).Config()
+	if c.Frames != len(spinner.Moon) || c.Interval != time.Second || c.ColorSource != spinner.SourceDefault {
+		t.Errorf("invalid variables changed the settings: Config() = %+v", c)
+	}
+	s, err := spinner.NewWithError(opts...)
+	if err != nil {
+		t.Fatalf("NewWithError = %v, want invalid variables ignored", err)
+	}
+	err = s.EnvErrors()
+	if err == nil {
+		t.Fatal("EnvErrors = nil for invalid variables")
+	}
+	for _, name := range []string{"SPINNER_STYLE", "SPINNER_INTERVAL", "SPINNER_COLOR", "SPINNER_DISABLED"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not mention %s", err, name)
+		}
+	}
+}
+
+func TestEnvDisabled(t *testing.T) {
+	t.Setenv("SPINNER_DISABLED", "1")
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithColorMode(spinner.ColorNever), spinner.WithEnvOverrides())
+	s.Start()
+	if s.Snapshot().Active {
+		t.Error("disabled spinner is active after Start")
+	}
+	s.Stop()
+	s.Success("done")
+	if got := buf.String(); got != "✔ done\n" {
+		t.Errorf("disabled spinner wrote %q, want only the final line", got)
+	}
+}
@@ -0,0 +1,36 @@
+package spinner
+
+import (
+	"io"
+	"time"
+)
+
+// SetTTY overrides terminal detection for s.
+func SetTTY(s *Spinner, tty bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tty = tty
+}
+
+// RenderExited returns the channel closed when the render goroutine started
+// by the last Start returns.
+func RenderExited(s *Spinner) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
+// SetClock replaces the clock s reads the time from.
+func SetClock(s *Spinner, now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// WriterRegistered reports whether a lock is registered for w.
+func WriterRegistered(w io.Writer) bool {
+	writerLocks.Lock()
+	defer writerLocks.Unlock()
+	_, ok := writerLocks.locks[w]
+	return ok
+}
@@ -0,0 +1,96 @@
+package spinner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StyleFlag is a flag.Value that selects a registered style by name.
+type StyleFlag struct {
+	Style Style
+}
+
+func (f *StyleFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.Style.Name
+}
+
+func (f *StyleFlag) Set(name string) error {
+	st, ok := LookupStyle(name)
+	if !ok {
+		return fmt.Errorf("unknown style %q", name)
+	}
+	f.Style = st
+	return nil
+}
+
+// IntervalFlag is a flag.Value that parses a frame interval written as a Go
+// duration, such as "100ms". The interval must be positive.
+type IntervalFlag struct {
+	Interval time.Duration
+}
+
+func (f *IntervalFlag) String() string {
+	if f == nil || f.Interval == 0 {
+		return ""
+	}
+	return f.Interval.String()
+}
+
+func (f *IntervalFlag) Set(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("invalid interval %q: want a duration such as 100ms", v)
+	}
+	if d <= 0 {
+		return fmt.Errorf("invalid interval %q: must be positive", v)
+	}
+	f.Interval = d
+	return nil
+}
+
+// ColorFlag is a flag.Value that parses a color given by name, such as
+// "red", as a 256-color number, either plain or written as "256:n", or as a
+// 24-bit color written "#rrggbb". Color holds the resulting escape sequence.
+type ColorFlag struct {
+	Color string
+	name  string
+}
+
+func (f *ColorFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.name
+}
+
+func (f *ColorFlag) Set(v string) error {
+	color, err := parseColor(v)
+	if err != nil {
+		return err
+	}
+	f.Color, f.name = color, v
+	return nil
+}
+
+func parseColor(v string) (string, error) {
+	if color, ok := ColorByName(v); ok {
+		return color, nil
+	}
+	if hex, ok := strings.CutPrefix(v, "#"); ok {
+		rgb, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return "", fmt.Errorf("invalid color %q: want #rrggbb", v)
+		}
+		return RGB(int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff)), nil
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(v, "256:"))
+	if err != nil || n < 0 || n > 255 {
+		return "", fmt.Errorf("invalid color %q: want a color name, a number from 0 to 255 or #rrggbb", v)
+	}
+	return Color256(n), nil
+}
@@ -0,0 +1,115 @@
+package spinner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+const (
+	focusReportingOn  = "\033[?1004h"
+	focusReportingOff = "\033[?1004l"
+	focusIn           = "\033[I"
+	focusOut          = "\033[O"
+)
+
+// WithPauseOnBlur stops the spinner from redrawing while the terminal window
+// is not focused, to save CPU in long-lived programs, and resumes when focus
+// returns. Terminals report focus changes as input, so this only takes
+// effect for a spinner writing to a terminal whose input is read through
+// FocusReader. Without one, focus reporting is never turned on and the
+// option does nothing.
+func WithPauseOnBlur(enable bool) Option {
+	return func(s *Spinner) {
+		s.pauseOnBlur = enable
+	}
+}
+
+// FocusReader returns a reader that passes through everything read from r,
+// the terminal's input in raw mode, except the focus events requested by
+// WithPauseOnBlur, which it uses to pause and resume the spinner. An
+// event split across reads of r is still recognized: an escape sequence
+// that could start one is held back until the next read settles it. The
+// reader should be used in place of r from then on.
+func (s *Spinner) FocusReader(r io.Reader) io.Reader {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.focusInput = true
+	if s.state == stateRunning {
+		s.setFocusReporting(true)
+	}
+	return &focusReader{s: s, r: r}
+}
+
+// setFocusReporting asks the terminal to start or stop reporting focus
+// changes, if pausing on blur applies to this spinner.
+func (s *Spinner) setFocusReporting(on bool) {
+	if on == s.focusReporting || on && !(s.pauseOnBlur && s.focusInput && s.tty && !s.plain) {
+		return
+	}
+	s.focusReporting = on
+	if on {
+		fmt.Fprint(s.writer, focusReportingOn)
+	} else {
+		fmt.Fprint(s.writer, focusReportingOff)
+	}
+}
+
+type focusReader struct {
+	s    *Spinner
+	r    io.Reader
+	held []byte // the start of a focus event cut off by the end of a read
+}
+
+func (f *focusReader) Read(p []byte) (int, error) {
+	for {
+		if len(p) > 0 && len(p) <= len(f.held) {
+			// No room to read the rest of the event; pass on what is held.
+			n := copy(p, f.held)
+			f.held = f.held[n:]
+			return n, nil
+		}
+		k := copy(p, f.held)
+		f.held = f.held[:0]
+		n, err := f.r.Read(p[k:])
+		n = f.filter(p[:k+n], err != nil)
+		if n > 0 || err != nil || len(p) == 0 {
+			return n, err
+		}
+	}
+}
+
+// filter removes focus events from p, applying each in turn, and returns
+// the length of what is left. Unless the input has ended, a trailing start
+// of an event is held back for the next read to complete.
+func (f *focusReader) filter(p []byte, ended bool) int {
+	if bytes.IndexByte(p, '\033') < 0 {
+		return len(p)
+	}
+	out := p[:0]
+	for len(p) > 0 {
+		switch {
+		case bytes.HasPrefix(p, []byte(focusIn)):
+			f.s.setBlurred(false)
+			p = p[len(focusIn):]
+		case bytes.HasPrefix(p, []byte(focusOut)):
+			f.s.setBlurred(true)
+			p = p[len(focusOut):]
+		case !ended && len(p) < len(focusIn) && bytes.HasPrefix([]byte(focusIn), p):
+			f.held = append(f.held, p...)
+			p = nil
+		default:
+			out = append(out, p[0])
+			p = p[1:]
+		}
+	}
+	return len(out)
+}
+
+func (s *Spinner) setBlurred(blurred bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.focusReporting {
+		s.blurred = blurred
+	}
+}
@@ -0,0 +1,112 @@
+package spinner_test
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+// lockedBuffer is a strings.Builder safe for the spinner and a test to use
+// at once.
+type lockedBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func TestPauseOnBlur(t *testing.T) {
+	var out lockedBuffer
+	s := spinner.New(spinner.WithWriter(&out), spinner.WithForceTTY(true), spinner.WithInterval(time.Millisecond), spinner.WithPauseOnBlur(true))
+	pr, pw := io.Pipe()
+	in := s.FocusReader(pr)
+	s.Start()
+
+	read := func(want string) {
+		t.Helper()
+		buf := make([]byte, 64)
+		n, err := in.Read(buf)
+		if err != nil || string(buf[:n]) != want {
+			t.Fatalf("Read = %q, %v; want %q", buf[:n], err, want)
+		}
+	}
+	go pw.Write([]byte("ab\033[Ocd"))
+	read("abcd")
+	time.Sleep(5 * time.Millisecond) // let a tick already under way finish
+	paused := out.String()
+	time.Sleep(20 * time.Millisecond)
+	if got := out.String(); got != paused {
+		t.Errorf("spinner drew %q while the terminal was unfocused", got[len(paused):])
+	}
+	go pw.Write([]byte("\033[Ie"))
+	read("e")
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+
+	got := out.String()
+	// Stop's clear accounts for two carriage returns; redraws add more.
+	if n := strings.Count(got[len(paused):], "\r"); n < 4 {
+		t.Errorf("spinner did not resume after focus returned")
+	}
+	if !strings.HasPrefix(got, "\033[?25l\033[?1004h") || !strings.HasSuffix(got, "\033[?1004l") {
+		t.Errorf("output %q does not turn focus reporting on and off", got)
+	}
+}
+
+func TestPauseOnBlurWithoutInput(t *testing.T) {
+	var out lockedBuffer
+	s := spinner.New(spinner.WithWriter(&out), spinner.WithForceTTY(true), spinner.WithPauseOnBlur(true))
+	s.Start()
+	s.Stop()
+	if strings.Contains(out.String(), "1004") {
+		t.Errorf("focus reporting turned on without a FocusReader: %q", out.String())
+	}
+}
+
+// chunkReader returns its chunks one read at a time.
+type chunkReader [][]byte
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(*r) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, (*r)[0])
+	if (*r)[0] = (*r)[0][n:]; len((*r)[0]) == 0 {
+		*r = (*r)[1:]
+	}
+	return n, nil
+}
+
+func TestFocusReaderSplit(t *testing.T) {
+	const (
+		input = "a\033[Ob\033[Ic\033[Ad\033"
+		want  = "abc\033[Ad\033"
+	)
+	for i := 0; i <= len(input); i++ {
+		s := spinner.New(spinner.WithWriter(io.Discard))
+		got, err := io.ReadAll(s.FocusReader(&chunkReader{[]byte(input[:i]), []byte(input[i:])}))
+		if err != nil || string(got) != want {
+			t.Errorf("split at %d: read %q, %v; want %q", i, got, err, want)
+		}
+	}
+	s := spinner.New(spinner.WithWriter(io.Discard))
+	got, err := io.ReadAll(s.FocusReader(iotest.OneByteReader(strings.NewReader(input))))
+	if err != nil || string(got) != want {
+		t.Errorf("one byte at a time: read %q, %v; want %q", got, err, want)
+	}
+}
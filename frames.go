@@ -0,0 +1,92 @@
+package spinner
+
+import (
+	"strings"
+	"time"
+)
+
+// eighths are the left-aligned blocks from one eighth of a cell to a full
+// cell, and levels the bottom-aligned ones.
+var (
+	eighths = []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉", "█"}
+	levels  = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+)
+
+// GrowHorizontalFrames returns frames for a bar cells columns wide that
+// fills from left to right in eighths of a cell and then drains again.
+// Every frame is exactly cells columns wide.
+func GrowHorizontalFrames(cells int) []string {
+	if cells < 1 {
+		return nil
+	}
+	n := 8 * cells
+	frames := make([]string, 0, 2*n)
+	bar := func(k int) string {
+		s := strings.Repeat("█", k/8)
+		if k%8 > 0 {
+			s += eighths[k%8-1]
+		}
+		return s + padding(cells-stringWidth(s))
+	}
+	for k := 0; k <= n; k++ {
+		frames = append(frames, bar(k))
+	}
+	for k := n - 1; k > 0; k-- {
+		frames = append(frames, bar(k))
+	}
+	return frames
+}
+
+// GrowVerticalFrames returns frames for cells columns of rising and falling
+// blocks, like a small equalizer. Each column follows the same rise and fall
+// as GrowVertical, two steps behind the column to its left.
+func GrowVerticalFrames(cells int) []string {
+	if cells < 1 {
+		return nil
+	}
+	steps := 2 * (len(levels) - 1)
+	level := func(i int) string {
+		i %= steps
+		if i >= len(levels) {
+			i = steps - i
+		}
+		return levels[i]
+	}
+	frames := make([]string, steps)
+	for f := range frames {
+		var b strings.Builder
+		for c := 0; c < cells; c++ {
+			b.WriteString(level(f + steps - 2*c%steps))
+		}
+		frames[f] = b.String()
+	}
+	return frames
+}
+
+// WallClockFrame returns a frame func for WithFrameFunc that shows the
+// frame for the local time of day rather than the next in turn: frames
+// advance once every step, counting from midnight, and start over after
+// the last. Spinners using it stay in step with the clock and with each
+// other however often they redraw.
+func WallClockFrame(frames []string, step time.Duration) func() string {
+	return wallClockFrame(frames, step, time.Now)
+}
+
+// ClockFrame returns a frame func for WithFrameFunc that turns the Clock
+// style into a live clock, showing the face for the current hour.
+func ClockFrame() func() string {
+	return WallClockFrame(Clock, time.Hour)
+}
+
+func wallClockFrame(frames []string, step time.Duration, now func() time.Time) func() string {
+	step = max(step, 1)
+	return func() string {
+		if len(frames) == 0 {
+			return ""
+		}
+		t := now()
+		h, m, sec := t.Clock()
+		day := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+		return frames[int(day/step)%len(frames)]
+	}
+}
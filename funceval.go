@@ -0,0 +1,142 @@
+package spinner
+
+import "time"
+
+// Color and interval funcs given with WithColorFunc, WithColorFrameFunc and
+// WithIntervalFunc are the caller's code, which may be slow: it may do I/O
+// or wait for a lock. So that such a func can never hold up Stop or
+// UpdateMessage, it is not called with the spinner's lock held. Each tick
+// asks a goroutine that lives as long as the run to call it for the coming
+// frame and waits at most one interval for the result. If the func takes
+// longer, the frame is drawn with the previous color and interval, the
+// overrun is counted in Stats.SlowFuncs, and no new call is made until the
+// slow one returns. Calls are never made concurrently, so a func need not
+// be safe for concurrent use.
+
+// fallbackInterval is the interval used until an interval func has
+// returned for the first time.
+const fallbackInterval = 60 * time.Millisecond
+
+// wrapFuncs puts the spinner's color and interval funcs, if they are the
+// caller's, behind the values evalFuncs stores. The caller must hold mu or
+// be configuring the spinner.
+func (s *Spinner) wrapFuncs() {
+	s.evalRun++ // results for the funcs being replaced are discarded
+	s.userColor, s.userInterval = nil, nil
+	s.colorValue, s.intervalValue = "", fallbackInterval
+	if s.colorSource == SourceFunc && s.color != nil {
+		s.userColor = s.color
+		s.color = func(FrameInfo) string { return s.colorValue }
+	}
+	if s.intervalSource == SourceFunc && s.interval != nil {
+		s.userInterval = s.interval
+		s.interval = func() time.Duration { return s.intervalValue }
+	}
+}
+
+// evalRequest asks the evaluator to call the caller's funcs for a frame.
+type evalRequest struct {
+	seq      int
+	run      int
+	info     FrameInfo
+	color    func(FrameInfo) string
+	interval func() time.Duration
+}
+
+// startEvaluator starts the goroutine that calls the caller's funcs for
+// the run that is starting, if there are any. It lives until stop is
+// closed, so that a tick only hands it a request rather than starting a
+// goroutine of its own. A call still under way from an earlier run keeps
+// evaluating set until it returns, so that the funcs are never called
+// concurrently, and its results are discarded. The caller must hold mu.
+func (s *Spinner) startEvaluator() {
+	s.evalRun++
+	s.evalReqs, s.evalDone = nil, nil
+	if s.userColor == nil && s.userInterval == nil {
+		return
+	}
+	s.evalReqs, s.evalDone = make(chan evalRequest, 1), make(chan int, 1)
+	if s.evalTimer == nil {
+		s.evalTimer = time.NewTimer(time.Hour)
+		s.evalTimer.Stop()
+	}
+	go s.evaluate(s.stop, s.evalReqs, s.evalDone)
+}
+
+// evaluate serves the requests of one run until stop is closed.
+func (s *Spinner) evaluate(stop <-chan struct{}, reqs <-chan evalRequest, done chan<- int) {
+	for {
+		var req evalRequest
+		select {
+		case <-stop:
+			return
+		case req = <-reqs:
+		}
+		var c string
+		var d time.Duration
+		if req.color != nil {
+			c = req.color(req.info)
+		}
+		if req.interval != nil {
+			d = req.interval()
+		}
+		s.mu.Lock()
+		if req.run == s.evalRun && req.color != nil {
+			s.colorValue = c
+		}
+		if req.run == s.evalRun && req.interval != nil {
+			s.intervalValue = d
+		}
+		s.evaluating = false
+		s.mu.Unlock()
+		select {
+		case <-stop:
+			return
+		case done <- req.seq:
+		}
+	}
+}
+
+// evalFuncs has the caller's color and interval funcs called for the frame
+// about to be drawn without holding mu, waiting for them for at most the
+// current interval or until the spinner is stopped. It is called only by
+// the goroutine that ticks, which owns evalTimer.
+func (s *Spinner) evalFuncs() {
+	s.mu.Lock()
+	color, interval := s.userColor, s.userInterval
+	if color == nil && interval == nil || s.evaluating || s.state != stateRunning || s.evalReqs == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.evalSeq++
+	req := evalRequest{s.evalSeq, s.evalRun, s.frameInfo(), color, interval}
+	limit := max(s.interval(), time.Millisecond)
+	stop, reqs, done, timer := s.stop, s.evalReqs, s.evalDone, s.evalTimer
+	s.evaluating = true
+	s.mu.Unlock()
+
+	reqs <- req
+	timer.Reset(limit)
+	expired := false
+wait:
+	for {
+		select {
+		case seq := <-done:
+			if seq == req.seq {
+				break wait
+			}
+			// The answer to an earlier request that overran.
+		case <-stop:
+			break wait
+		case <-timer.C:
+			expired = true
+			s.mu.Lock()
+			s.slowFuncs++
+			s.mu.Unlock()
+			break wait
+		}
+	}
+	if !expired && !timer.Stop() {
+		<-timer.C
+	}
+}
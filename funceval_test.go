@@ -0,0 +1,93 @@
+package spinner_test
+
+import (
+	"bytes"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestSlowColorFunc(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	release := make(chan struct{})
+	defer close(release)
+	var calls atomic.Int32
+	// The first call returns at once; the second blocks until the test ends.
+	color := func() string {
+		if calls.Add(1) > 1 {
+			<-release
+		}
+		return spinner.Red
+	}
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithInterval(10*time.Millisecond),
+		spinner.WithColorFunc(color), spinner.WithColorMode(spinner.ColorAlways))
+	s.Start()
+	time.Sleep(100 * time.Millisecond)
+	s.UpdateMessage("still drawing")
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	s.Stop()
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Stop took %v with a blocked color func", d)
+	}
+	st := s.Stats()
+	if st.SlowFuncs == 0 {
+		t.Error("Stats.SlowFuncs = 0, want the blocked call counted")
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("color func called %d times, want 2: no call while one is under way", n)
+	}
+	// Frames went on being drawn with the last color the func returned.
+	if out := buf.String(); st.Ticks < 5 || !strings.Contains(out, spinner.Red) || !strings.Contains(out, "still drawing") {
+		t.Errorf("%d ticks, output %q", st.Ticks, out)
+	}
+}
+
+func TestSlowColorFuncRestart(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	release := make(chan struct{})
+	var calls, inFlight atomic.Int32
+	var concurrent atomic.Bool
+	// The first call blocks until released and returns red; later calls
+	// return blue at once.
+	color := func() string {
+		if inFlight.Add(1) > 1 {
+			concurrent.Store(true)
+		}
+		defer inFlight.Add(-1)
+		if calls.Add(1) == 1 {
+			<-release
+			return spinner.Red
+		}
+		return spinner.Blue
+	}
+	var buf lockedBuffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithInterval(5*time.Millisecond),
+		spinner.WithColorFunc(color), spinner.WithColorMode(spinner.ColorAlways))
+	s.Start()
+	time.Sleep(30 * time.Millisecond)
+	s.Stop()
+	s.Start()
+	time.Sleep(30 * time.Millisecond)
+	if n := calls.Load(); n != 1 {
+		t.Errorf("color func called %d times while the first call was under way", n)
+	}
+	close(release)
+	time.Sleep(30 * time.Millisecond)
+	s.Stop()
+
+	if concurrent.Load() {
+		t.Error("color func called concurrently")
+	}
+	if calls.Load() < 2 {
+		t.Error("color func not called again after the blocked call returned")
+	}
+	if out := buf.String(); strings.Contains(out, spinner.Red) || !strings.Contains(out, spinner.Blue) {
+		t.Errorf("output %q: the previous run's result was used", out)
+	}
+}
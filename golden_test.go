@@ -0,0 +1,353 @@
+package spinner
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+// step is one entry of a golden script: the clock moves forward by advance,
+// the message is replaced if message is set, and then a frame is drawn.
+type step struct {
+	advance time.Duration
+	message string
+}
+
+// every returns n steps of d each.
+func every(d time.Duration, n int) []step {
+	steps := make([]step, n)
+	for i := range steps {
+		steps[i].advance = d
+	}
+	return steps
+}
+
+// manualLoop stands in for the render loop in tests that call tick
+// themselves.
+func manualLoop(stop <-chan struct{}, exited chan<- struct{}) {
+	<-stop
+	close(exited)
+}
+
+// script runs a spinner built from opts through steps against a fake clock,
+// without a render goroutine, and ends it with finish. The spinner takes its
+// writer for a terminal and draws without color unless opts say otherwise.
+// It returns everything the spinner wrote.
+func script(opts []Option, steps []step, finish func(*Spinner)) []byte {
+	var buf bytes.Buffer
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := New(append([]Option{WithWriter(&buf), WithForceTTY(true), WithColorMode(ColorNever)}, opts...)...)
+	s.now = clock.now
+	s.loop = manualLoop
+	s.Start()
+	for _, st := range steps {
+		clock.t = clock.t.Add(st.advance)
+		if st.message != "" {
+			s.mu.Lock()
+			s.message = st.message
+			s.mu.Unlock()
+		}
+		if !s.plain {
+			s.tick()
+		}
+	}
+	finish(s)
+	return buf.Bytes()
+}
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		term  string
+		opts  []Option
+		steps []step
+		end   func(*Spinner)
+	}{
+		{
+			name:  "default",
+			steps: every(60*time.Millisecond, 12),
+			end:   (*Spinner).Stop,
+		},
+		{
+			name:  "color",
+			opts:  []Option{WithColorMode(ColorAlways), WithColor(Blue), WithFrames(Line)},
+			steps: every(60*time.Millisecond, 5),
+			end:   (*Spinner).Stop,
+		},
+		{
+			name: "message_elapsed",
+			opts: []Option{WithMessage("Downloading"), WithElapsed(true)},
+			steps: append(every(400*time.Millisecond, 4),
+				step{advance: 400 * time.Millisecond, message: "Unpacking"},
+				step{advance: 400 * time.Millisecond},
+				step{advance: 10 * time.Second}),
+			end: (*Spinner).Stop,
+		},
+		{
+			name:  "success",
+			opts:  []Option{WithMessage("Building"), WithColorMode(ColorAlways)},
+			steps: every(60*time.Millisecond, 3),
+			end:   func(s *Spinner) { s.Success("Built") },
+		},
+		{
+			name:  "plain",
+			term:  "dumb",
+			opts:  []Option{WithMessage("Working"), WithSuffix("...")},
+			steps: every(60*time.Millisecond, 3),
+			end:   func(s *Spinner) { s.Fail("Failed") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := tt.term
+			if term == "" {
+				term = "xterm"
+			}
+			t.Setenv("TERM", term)
+			checkGolden(t, tt.name, script(tt.opts, tt.steps, tt.end))
+		})
+	}
+}
+
+func TestFrameGeneratorsGolden(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		frames []string
+	}{
+		{"grow_horizontal_1", GrowHorizontalFrames(1)},
+		{"grow_horizontal_2", GrowHorizontalFrames(2)},
+		{"grow_vertical_1", GrowVerticalFrames(1)},
+		{"grow_vertical_3", GrowVerticalFrames(3)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			for _, f := range tt.frames {
+				fmt.Fprintf(&b, "%q\n", f)
+			}
+			checkGolden(t, tt.name, b.Bytes())
+		})
+	}
+}
+
+// checkGolden compares got with testdata/name.golden, or rewrites the file
+// when the -update flag is set.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\ngot  %q\nwant %q", path, got, want)
+	}
+}
+
+func TestSkipDuplicateFrames(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	frames := []string{"a", "a", "b"}
+	for _, skip := range []bool{false, true} {
+		var lines []string
+		var s *Spinner
+		script([]Option{WithFrames(frames), WithRecorder(&lines), WithSkipDuplicateFrames(skip)},
+			every(time.Millisecond, 7), func(sp *Spinner) { s = sp; sp.Stop() })
+		want := []string{"a", "a", "b", "a", "a", "b", "a"}
+		if skip {
+			want = []string{"a", "b", "a", "b", "a"}
+		}
+		if !reflect.DeepEqual(lines, want) {
+			t.Errorf("skip=%v: lines = %q, want %q", skip, lines, want)
+		}
+		if got := s.Index(); got != 1 {
+			t.Errorf("skip=%v: index after 7 ticks = %d, want 1", skip, got)
+		}
+	}
+}
+
+// countingWriter counts calls to Write.
+type countingWriter struct{ n int }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n++
+	return len(p), nil
+}
+
+func BenchmarkSkipDuplicateFrames(b *testing.B) {
+	b.Setenv("TERM", "xterm")
+	for _, skip := range []bool{false, true} {
+		b.Run(fmt.Sprintf("skip=%v", skip), func(b *testing.B) {
+			var w countingWriter
+			s := New(WithWriter(&w), WithFrames(Material), WithSkipDuplicateFrames(skip))
+			s.loop = manualLoop
+			s.Start()
+			w.n = 0
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.tick()
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(w.n)/float64(b.N), "writes/tick")
+			s.Stop()
+		})
+	}
+}
+
+func TestMinRenderInterval(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var lines []string
+	var s *Spinner
+	script([]Option{WithFrames([]string{"a", "b", "c", "d"}), WithRecorder(&lines), WithMinRenderInterval(25 * time.Millisecond)},
+		every(10*time.Millisecond, 7), func(sp *Spinner) { s = sp; sp.Stop() })
+	// Frames are drawn at 10ms, 40ms and 70ms; the ticks between only advance.
+	if want := []string{"a", "d", "c"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	if got := s.Index(); got != 3 {
+		t.Errorf("index after 7 ticks = %d, want 3", got)
+	}
+}
+
+func TestNoFrames(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"message", nil, "Copying 42%"},
+		{"bar", []Option{WithProgressBar(5)}, "██░░░ Copying 42%"},
+		{"frame func", []Option{WithFrameFunc(func() string { return "*" })}, "* Copying 42%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			opts := append([]Option{WithWriter(io.Discard), WithFrames(nil), WithMessage("Copying"), WithColorMode(ColorNever), WithRecorder(&lines)}, tt.opts...)
+			s := New(opts...)
+			s.loop = manualLoop
+			s.SetProgress(21, 50)
+			s.SetIndex(2)
+			s.Start()
+			s.tick()
+			s.tick()
+			s.Stop()
+			if want := []string{tt.want, tt.want}; !reflect.DeepEqual(lines, want) {
+				t.Errorf("lines = %q, want %q", lines, want)
+			}
+		})
+	}
+}
+
+func TestWallClockFrame(t *testing.T) {
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)}
+	hours := wallClockFrame(Clock, time.Hour, clock.now)
+	seconds := wallClockFrame([]string{"a", "b", "c"}, time.Second, clock.now)
+	tests := []struct {
+		at            time.Duration // since midnight
+		hour, seconds string
+	}{
+		{0, "🕛 ", "a"},
+		{time.Hour - 1, "🕛 ", "c"},
+		{time.Hour, "🕐 ", "a"},
+		{3*time.Hour + 1500*time.Millisecond, "🕒 ", "b"},
+		{13*time.Hour + 2*time.Second, "🕐 ", "c"},
+		{23*time.Hour + 59*time.Minute, "🕚 ", "a"},
+	}
+	for _, tt := range tests {
+		clock.t = time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local).Add(tt.at)
+		if got := hours(); got != tt.hour {
+			t.Errorf("clock face at %v = %q, want %q", tt.at, got, tt.hour)
+		}
+		if got := seconds(); got != tt.seconds {
+			t.Errorf("per-second frame at %v = %q, want %q", tt.at, got, tt.seconds)
+		}
+	}
+	if got := wallClockFrame(nil, time.Second, clock.now)(); got != "" {
+		t.Errorf("frame with no frames = %q", got)
+	}
+}
+
+func TestFinalFrame(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	tests := []struct {
+		name string
+		opts []Option
+		end  func(*Spinner)
+		want string
+	}{
+		{"stop", nil, (*Spinner).Stop, "✔ Building"},
+		{"stopf", nil, func(s *Spinner) { s.Stopf("built") }, "✔ Building\nbuilt"},
+		{"success", nil, func(s *Spinner) { s.Success("built") }, "✔ built"},
+		{"fail", nil, func(s *Spinner) { s.Fail("broken") }, "✖ broken"},
+		{"clear on stop", []Option{WithClearOnStop(true)}, (*Spinner).Stop, ""},
+		{"wider frames", []Option{WithFrames([]string{"[==]", "[ =]"})}, (*Spinner).Stop, "✔ Building"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := new(screen)
+			opts := append([]Option{WithWriter(sc), WithFrames(Line), WithMessage("Building"), WithColorMode(ColorNever), WithClearOnStop(false), WithFinalFrame("✔")}, tt.opts...)
+			script(opts, every(time.Millisecond, 3), tt.end)
+			if got := sc.String(); got != tt.want {
+				t.Errorf("screen = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequentialRuns(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var lines []string
+	s := New(WithWriter(io.Discard), WithFrames([]string{"a", "b", "c"}), WithColor(Red), WithColorMode(ColorAlways), WithRecorder(&lines))
+	s.loop = manualLoop
+	run := func(ticks int) (done <-chan struct{}) {
+		s.Start()
+		done = s.Done()
+		for range ticks {
+			s.tick()
+		}
+		s.Stop()
+		return done
+	}
+
+	first := run(2)
+	s.SetFrames([]string{"x", "y"})
+	s.SetColor(Green)
+	second := run(3)
+	s.SetColor(Blue)
+	third := run(1)
+
+	want := []string{
+		Red + "a" + Reset, Red + "b" + Reset,
+		Green + "x" + Reset, Green + "y" + Reset, Green + "x" + Reset,
+		Blue + "x" + Reset,
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	if first == second || second == third {
+		t.Error("runs share a Done channel")
+	}
+	if st := s.Stats(); st.Starts != 3 || st.Stops != 3 || st.Ticks != 1 || st.TotalTicks != 6 {
+		t.Errorf("Stats = %+v, want 3 runs and 6 ticks, 1 in the last", st)
+	}
+}
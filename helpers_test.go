@@ -0,0 +1,171 @@
+package spinner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimedColors(t *testing.T) {
+	colors := []string{"a", "b", "c"}
+	tests := []struct {
+		name     string
+		pingPong bool
+		want     string // color at each 100ms step
+	}{
+		{"cycle", false, "abcabcabc"},
+		{"pulse", true, "abcbabcba"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{time.Unix(0, 0)}
+			period := 300 * time.Millisecond
+			if tt.pingPong {
+				period = 400 * time.Millisecond
+			}
+			f := timedColors(colors, period, tt.pingPong, clock.now)
+			var got string
+			for range tt.want {
+				got += f()
+				f() // calling again without time passing changes nothing
+				clock.t = clock.t.Add(100 * time.Millisecond)
+			}
+			if got != tt.want {
+				t.Errorf("colors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreyPulse(t *testing.T) {
+	f := GreyPulse(time.Hour)
+	if got, want := f(), Color256(238); got != want {
+		t.Errorf("first color = %q, want %q", got, want)
+	}
+}
+
+func TestThresholdColor(t *testing.T) {
+	f := ThresholdColor(
+		ColorThreshold{0, White},
+		ColorThreshold{10 * time.Second, Yellow},
+		ColorThreshold{30 * time.Second, Red},
+	)
+	tests := []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{0, White},
+		{10*time.Second - 1, White},
+		{10 * time.Second, Yellow},
+		{30*time.Second - 1, Yellow},
+		{30 * time.Second, Red},
+		{time.Hour, Red},
+	}
+	for _, tt := range tests {
+		if got := f(FrameInfo{Elapsed: tt.elapsed}); got != tt.want {
+			t.Errorf("color at %v = %q, want %q", tt.elapsed, got, tt.want)
+		}
+	}
+
+	t.Setenv("TERM", "xterm")
+	var lines []string
+	script([]Option{
+		WithColorMode(ColorAlways),
+		WithFrames([]string{"-"}),
+		WithRecorder(&lines),
+		WithColorFrameFunc(ThresholdColor(ColorThreshold{time.Second, Green})),
+	}, every(600*time.Millisecond, 2), (*Spinner).Stop)
+	if want := []string{"-" + Reset, Green + "-" + Reset}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
+
+func TestETA(t *testing.T) {
+	clock := &fakeClock{time.Unix(0, 0)}
+	f := eta(clock.now)
+	steps := []struct {
+		advance  time.Duration
+		fraction float64
+		want     time.Duration
+	}{
+		{0, 0, 0},                           // not started
+		{time.Second, 0.1, 9 * time.Second}, // 0.1/s
+		{time.Second, 0.2, 8 * time.Second}, // still 0.1/s
+		{time.Second, 0.2, 8 * time.Second}, // stalled: estimate holds
+		{time.Second, 0.4, 6 * time.Second}, // 0.1/s over the stall
+		{time.Second, 0.1, 0},               // went backwards
+		{time.Second, 0.2, 8 * time.Second}, // measuring again
+		{time.Second, 1, 0},                 // done
+	}
+	for i, st := range steps {
+		clock.t = clock.t.Add(st.advance)
+		if got := f(st.fraction); got.Round(time.Millisecond) != st.want {
+			t.Errorf("step %d: ETA(%v) = %v, want %v", i, st.fraction, got, st.want)
+		}
+	}
+
+	// A faster sample pulls the estimate down only partway.
+	f = eta(clock.now)
+	f(0.1)
+	clock.t = clock.t.Add(time.Second)
+	f(0.2)
+	clock.t = clock.t.Add(time.Second)
+	got := f(0.5) // 0.3/s against 0.1/s so far
+	if want := time.Duration(0.5 / 0.16 * float64(time.Second)); got.Round(time.Millisecond) != want.Round(time.Millisecond) {
+		t.Errorf("smoothed ETA = %v, want %v", got, want)
+	}
+}
+
+func TestColor256(t *testing.T) {
+	tests := []struct {
+		n       int
+		want    string
+		wantErr bool
+	}{
+		{-1, "\033[38;5;0m", true},
+		{0, "\033[38;5;0m", false},
+		{255, "\033[38;5;255m", false},
+		{256, "\033[38;5;255m", true},
+	}
+	for _, tt := range tests {
+		if got := Color256(tt.n); got != tt.want {
+			t.Errorf("Color256(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+		got, err := Color256Checked(tt.n)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Color256Checked(%d) error = %v, want error %v", tt.n, err, tt.wantErr)
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("Color256Checked(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRGB(t *testing.T) {
+	if got, want := RGB(-1, 128, 256), "\033[38;2;0;128;255m"; got != want {
+		t.Errorf("RGB = %q, want %q", got, want)
+	}
+	if _, err := RGBChecked(0, 255, 256); err == nil {
+		t.Error("RGBChecked(0, 255, 256) returned no error")
+	}
+	if got, err := RGBChecked(0, 128, 255); err != nil || got != "\033[38;2;0;128;255m" {
+		t.Errorf("RGBChecked(0, 128, 255) = %q, %v", got, err)
+	}
+}
+
+func TestColorPulse(t *testing.T) {
+	clock := &fakeClock{time.Unix(0, 0)}
+	f := colorPulse(1, 3, 100*time.Millisecond, clock.now)
+	var got []string
+	for range 9 {
+		got = append(got, f())
+		clock.t = clock.t.Add(101 * time.Millisecond)
+	}
+	var want []string
+	for _, n := range []int{1, 2, 3, 2, 1, 2, 3, 2, 1} {
+		want = append(want, Color256(n))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("colors = %q, want %q", got, want)
+	}
+}
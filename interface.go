@@ -0,0 +1,22 @@
+package spinner
+
+// Spinners is the part of a spinner's behavior that code reporting on its
+// work usually needs. Code that accepts a Spinners instead of a *Spinner can
+// be given a fake in tests. *Spinner implements it, and NewNoop returns one
+// that does nothing.
+type Spinners interface {
+	Start()
+	Stop()
+	UpdateMessage(msg string)
+	IsActive() bool
+	Success(msg string)
+	Fail(msg string)
+}
+
+var _ Spinners = (*Spinner)(nil)
+
+// IsActive reports whether the spinner is running. It does not take the
+// spinner's lock, so it is cheap to call from any goroutine.
+func (s *Spinner) IsActive() bool {
+	return s.active.Load()
+}
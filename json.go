@@ -0,0 +1,90 @@
+package spinner
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Event is one line of the output of a spinner with WithJSONOutput. Each
+// event is written as a JSON object followed by a newline, and the field
+// names given by the tags are stable. A run writes a "start" event, then a
+// "progress" event at every WithJSONInterval while it runs, then a "done"
+// event.
+type Event struct {
+	Event     string    `json:"event"` // "start", "progress" or "done"
+	Time      time.Time `json:"ts"`
+	Message   string    `json:"msg,omitempty"`
+	Percent   *int64    `json:"percent,omitempty"` // set once SetProgress has reported a total
+	Current   int64     `json:"current,omitempty"`
+	Total     int64     `json:"total,omitempty"`
+	ElapsedMS int64     `json:"elapsed_ms"`
+	// Status says how a done event's run ended: "success", "fail" or
+	// "warn" after Success, Fail or Warn, and "stopped" otherwise.
+	Status string `json:"status,omitempty"`
+}
+
+// WithJSONOutput replaces the animation with Events written to w, one JSON
+// object per line, for programs whose output is read by another program
+// rather than a person. Nothing else is written to w: no frames, colors,
+// escape sequences or carriage returns, whatever the terminal. Success,
+// Fail, Warn and Stopf report their message in the done event instead of
+// printing it. Println and Printf still write their text to w as is.
+func WithJSONOutput(w io.Writer) Option {
+	return func(s *Spinner) {
+		s.jsonOut = w
+	}
+}
+
+// WithJSONInterval sets how often a spinner with WithJSONOutput writes a
+// progress event. Events are written on the spinner's ticks, so they are
+// never more frequent than its interval. It defaults to one second.
+func WithJSONInterval(d time.Duration) Option {
+	return func(s *Spinner) {
+		s.jsonEvery = d
+	}
+}
+
+// event returns an event of the given kind describing the spinner now.
+func (s *Spinner) event(kind string) Event {
+	now := s.now()
+	ev := Event{
+		Event:     kind,
+		Time:      now,
+		Message:   s.message,
+		Current:   s.current,
+		Total:     s.total,
+		ElapsedMS: now.Sub(s.startedAt).Milliseconds(),
+	}
+	if s.total > 0 {
+		pct := percent(s.current, s.total)
+		ev.Percent = &pct
+	}
+	return ev
+}
+
+// writeEvent writes ev to the spinner's writer as a line of JSON.
+func (s *Spinner) writeEvent(ev Event) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = s.writer.Write(append(b, '\n'))
+	return err
+}
+
+// writeDone writes a done event with status. A non-empty msg replaces the
+// spinner's message. The elapsed time is that of the run being stopped, or
+// zero for a spinner that was not running.
+func (s *Spinner) writeDone(status, msg string) {
+	ev := s.event("done")
+	ev.Status = status
+	if msg != "" {
+		ev.Message = msg
+	}
+	ev.ElapsedMS = 0
+	if s.state == stateStopping {
+		ev.ElapsedMS = s.stoppedAt.Sub(s.startedAt).Milliseconds()
+	}
+	s.writeEvent(ev)
+}
@@ -0,0 +1,98 @@
+package spinner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// events decodes the lines of out, failing on anything that is not an
+// Event.
+func events(t *testing.T, out []byte) []Event {
+	t.Helper()
+	var evs []Event
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		var ev Event
+		dec := json.NewDecoder(bytes.NewReader(sc.Bytes()))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&ev); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		evs = append(evs, ev)
+	}
+	return evs
+}
+
+func TestJSONOutput(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var out bytes.Buffer
+	steps := every(100*time.Millisecond, 25)
+	steps[0].message = "building"
+	terminal := script([]Option{
+		WithJSONOutput(&out),
+		WithJSONInterval(time.Second),
+		WithColorMode(ColorAlways),
+		WithMessage("building"),
+		func(s *Spinner) { s.current, s.total = 21, 50 },
+	}, steps, func(s *Spinner) { s.Success("built") })
+	if len(terminal) != 0 {
+		t.Errorf("wrote %q to the spinner's writer", terminal)
+	}
+	evs := events(t, out.Bytes())
+	type summary struct {
+		Event, Message, Status string
+		ElapsedMS              int64
+		Percent                int64
+	}
+	var got []summary
+	for _, ev := range evs {
+		sum := summary{ev.Event, ev.Message, ev.Status, ev.ElapsedMS, -1}
+		if ev.Percent != nil {
+			sum.Percent = *ev.Percent
+		}
+		got = append(got, sum)
+	}
+	want := []summary{
+		{"start", "building", "", 0, 42},
+		{"progress", "building", "", 1000, 42},
+		{"progress", "building", "", 2000, 42},
+		{"done", "built", "success", 2500, 42},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %+v, want %+v", got, want)
+	}
+	if evs[0].Time.IsZero() || !evs[3].Time.After(evs[0].Time) {
+		t.Errorf("timestamps %v, %v", evs[0].Time, evs[3].Time)
+	}
+}
+
+func TestJSONOutputSchema(t *testing.T) {
+	var out bytes.Buffer
+	s := New(WithJSONOutput(&out), WithMessage("working"))
+	s.loop = manualLoop
+	s.Start()
+	s.Stop()
+	s.Fail("no")
+	s.Stop() // not running: writes nothing
+	var raw []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n")) {
+		var m map[string]any
+		if err := json.Unmarshal(line, &m); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		delete(m, "ts")
+		raw = append(raw, m)
+	}
+	want := []map[string]any{
+		{"event": "start", "msg": "working", "elapsed_ms": 0.0},
+		{"event": "done", "msg": "working", "elapsed_ms": 0.0, "status": "stopped"},
+		{"event": "done", "msg": "no", "elapsed_ms": 0.0, "status": "fail"},
+	}
+	if !reflect.DeepEqual(raw, want) {
+		t.Errorf("events = %v, want %v", raw, want)
+	}
+}
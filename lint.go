@@ -0,0 +1,161 @@
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidFrames is reported by NewWithError, when WithStrictFrames is
+// given, for frames that ValidateFrames finds errors in.
+var ErrInvalidFrames = errors.New("spinner: invalid frames")
+
+// maxFrames is the most frames a set may have before ValidateFrames warns
+// that it is unusually long.
+const maxFrames = 120
+
+// Severity tells how serious a Problem is.
+type Severity int
+
+const (
+	// SeverityWarning marks frames that work but may not look as intended.
+	SeverityWarning Severity = iota
+	// SeverityError marks frames that will corrupt the spinner's line.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ProblemKind identifies what ValidateFrames found wrong.
+type ProblemKind int
+
+const (
+	ProblemCount       ProblemKind = iota // no frames, a single one or very many
+	ProblemInvalidUTF8                    // a frame is not valid UTF-8
+	ProblemControl                        // a frame holds a control character
+	ProblemEscape                         // a frame holds an escape sequence
+	ProblemWidth                          // frames differ in display width
+)
+
+func (k ProblemKind) String() string {
+	switch k {
+	case ProblemCount:
+		return "count"
+	case ProblemInvalidUTF8:
+		return "invalid-utf8"
+	case ProblemControl:
+		return "control"
+	case ProblemEscape:
+		return "escape"
+	case ProblemWidth:
+		return "width"
+	}
+	return fmt.Sprintf("ProblemKind(%d)", int(k))
+}
+
+// Problem is an issue ValidateFrames found in a frame set.
+type Problem struct {
+	Index    int // frame the problem is in, or -1 for the set as a whole
+	Kind     ProblemKind
+	Severity Severity
+	Message  string
+}
+
+func (p Problem) String() string {
+	if p.Index < 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("frame %d: %s", p.Index, p.Message)
+}
+
+// ValidateFrames checks a frame set before it is used and returns the
+// problems it finds, or nil if there are none. It is an error for a frame
+// to be invalid UTF-8 or to hold control characters other than the line
+// breaks of multiline frames, and for the set to be empty. It is a warning
+// for frames to differ in width, which makes the text after them shift, to
+// carry escape sequences, which need WithRawFrames, and for the set to have
+// a single frame or very many. Widths are measured by the default rules,
+// which a spinner given WithWidthFunc or WithEmojiWidth does not follow,
+// taking the widest line of a multiline frame.
+func ValidateFrames(frames []string) []Problem {
+	var problems []Problem
+	add := func(i int, kind ProblemKind, sev Severity, format string, args ...any) {
+		problems = append(problems, Problem{i, kind, sev, fmt.Sprintf(format, args...)})
+	}
+	switch n := len(frames); {
+	case n == 0:
+		add(-1, ProblemCount, SeverityError, "no frames")
+	case n == 1:
+		add(-1, ProblemCount, SeverityWarning, "a single frame does not animate")
+	case n > maxFrames:
+		add(-1, ProblemCount, SeverityWarning, "%d frames, more than %d", n, maxFrames)
+	}
+	widths := make([]int, len(frames))
+	widest := 0
+	for i, f := range frames {
+		if !utf8.ValidString(f) {
+			add(i, ProblemInvalidUTF8, SeverityError, "invalid UTF-8 %q", f)
+			continue
+		}
+		escape, control := false, rune(-1)
+		for j := 0; j < len(f); {
+			r, n := utf8.DecodeRuneInString(f[j:])
+			switch {
+			case r == '\033' || r == 0x9b || r == 0x9d:
+				n, escape = escapeLen(f[j:]), true
+			case control < 0 && (r < 0x20 && r != '\n' || r >= 0x7f && r < 0xa0):
+				control = r
+			}
+			j += n
+		}
+		if escape {
+			add(i, ProblemEscape, SeverityWarning, "escape sequence in %q needs WithRawFrames", f)
+		}
+		if control >= 0 {
+			add(i, ProblemControl, SeverityError, "control character %U in %q", control, f)
+		}
+		for _, line := range strings.Split(f, "\n") {
+			widths[i] = max(widths[i], displayWidth(line))
+		}
+		widest = max(widest, widths[i])
+	}
+	for i, w := range widths {
+		if w != widest && utf8.ValidString(frames[i]) {
+			add(i, ProblemWidth, SeverityWarning, "%q is %d columns wide, the widest frame %d", frames[i], w, widest)
+		}
+	}
+	return problems
+}
+
+// WithStrictFrames makes NewWithError fail with ErrInvalidFrames if
+// ValidateFrames finds errors, rather than warnings, in the spinner's
+// frames. A spinner drawn by WithFrameFunc or WithFrameInfoFunc may have
+// no frames.
+func WithStrictFrames(strict bool) Option {
+	return func(s *Spinner) {
+		s.strictFrames = strict
+	}
+}
+
+// checkFrames adds the errors ValidateFrames finds in frames to the option
+// errors NewWithError reports.
+func (s *Spinner) checkFrames(frames []string) {
+	for _, p := range ValidateFrames(frames) {
+		if p.Kind == ProblemCount && s.frameFunc != nil {
+			// The frame func draws in place of the frames.
+			continue
+		}
+		if p.Severity == SeverityError {
+			s.optErrs = append(s.optErrs, fmt.Errorf("%w: %s", ErrInvalidFrames, p))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package spinner_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tmc/spinner"
+)
+
+func TestValidateFrames(t *testing.T) {
+	type found struct {
+		Index    int
+		Kind     spinner.ProblemKind
+		Severity spinner.Severity
+	}
+	tests := []struct {
+		name   string
+		frames []string
+		want   []found
+	}{
+		{"ok", []string{"a", "b"}, nil},
+		{"empty", nil, []found{{-1, spinner.ProblemCount, spinner.SeverityError}}},
+		{"single", []string{"a"}, []found{{-1, spinner.ProblemCount, spinner.SeverityWarning}}},
+		{"invalid UTF-8", []string{"a", "\xff"}, []found{{1, spinner.ProblemInvalidUTF8, spinner.SeverityError}}},
+		{"control", []string{"a\tb", "cd"}, []found{{0, spinner.ProblemControl, spinner.SeverityError}}},
+		{"escape", []string{"\033[31ma\033[0m", "b"}, []found{{0, spinner.ProblemEscape, spinner.SeverityWarning}}},
+		{"width", []string{"a ", "b", "❤️ "}, []found{
+			{0, spinner.ProblemWidth, spinner.SeverityWarning},
+			{1, spinner.ProblemWidth, spinner.SeverityWarning},
+		}},
+		{"multiline", []string{"a\nbb", "cc\nd"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []found
+			for _, p := range spinner.ValidateFrames(tt.frames) {
+				got = append(got, found{p.Index, p.Kind, p.Severity})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateFrames(%q) = %+v, want %+v", tt.frames, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinFramesValid(t *testing.T) {
+	for _, name := range spinner.StyleNames() {
+		st, _ := spinner.LookupStyle(name)
+		for _, p := range spinner.ValidateFrames(st.Frames) {
+			t.Errorf("%s: %s: %s", name, p.Severity, p)
+		}
+	}
+}
+
+func TestStrictFrames(t *testing.T) {
+	_, err := spinner.NewWithError(spinner.WithFrames([]string{"a", "b\x07"}), spinner.WithStrictFrames(true))
+	if !errors.Is(err, spinner.ErrInvalidFrames) {
+		t.Errorf("NewWithError with a control character = %v, want ErrInvalidFrames", err)
+	}
+	if _, err := spinner.NewWithError(spinner.WithFrames([]string{"a", "b\x07"})); err != nil {
+		t.Errorf("NewWithError without WithStrictFrames = %v", err)
+	}
+	if _, err := spinner.NewWithError(spinner.WithFrames([]string{"a", "bc"}), spinner.WithStrictFrames(true)); err != nil {
+		t.Errorf("NewWithError with only warnings = %v", err)
+	}
+	frame := spinner.WithFrameFunc(func() string { return "*" })
+	if _, err := spinner.NewWithError(spinner.WithFrames(nil), frame, spinner.WithStrictFrames(true)); err != nil {
+		t.Errorf("NewWithError with a frame func and no frames = %v", err)
+	}
+	if _, err := spinner.NewWithError(spinner.WithFrames(nil), spinner.WithStrictFrames(true)); !errors.Is(err, spinner.ErrInvalidFrames) {
+		t.Errorf("NewWithError with no frames = %v, want ErrInvalidFrames", err)
+	}
+}
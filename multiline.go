@@ -0,0 +1,110 @@
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMultilineFrames is reported on Errors when a spinner with frames that
+// span several lines is started without ANSI support. Such a spinner prints
+// its message once, as any spinner does on a dumb terminal, but the frames
+// are never drawn.
+var ErrMultilineFrames = errors.New("spinner: multiline frames need a terminal with ANSI support")
+
+// TallBounce is a ball bouncing between the top and bottom of three lines.
+var TallBounce = []string{"●\n \n ", " \n●\n ", " \n \n●", " \n●\n "}
+
+// isMultiline reports whether any of frames spans more than one line.
+func isMultiline(frames []string) bool {
+	for _, f := range frames {
+		if strings.Contains(f, "\n") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFrames returns frames with every frame given as many lines as the
+// tallest one and every line padded to the widest, so that each frame covers
+// all of the previous one. Frames on a single line are returned unchanged.
+// Lines are measured with width.
+func normalizeFrames(frames []string, width func(string) int) []string {
+	if !isMultiline(frames) {
+		return frames
+	}
+	height, widest := 0, 0
+	split := make([][]string, len(frames))
+	for i, f := range frames {
+		split[i] = strings.Split(f, "\n")
+		height = max(height, len(split[i]))
+		for _, line := range split[i] {
+			widest = max(widest, width(line))
+		}
+	}
+	out := make([]string, len(frames))
+	for i, lines := range split {
+		for len(lines) < height {
+			lines = append(lines, "")
+		}
+		for j, line := range lines {
+			lines[j] = line + padding(widest-width(line))
+		}
+		out[i] = strings.Join(lines, "\n")
+	}
+	return out
+}
+
+// drawLines is draw for text of several lines, or text replacing output
+// that had several. An inline spinner moves the cursor back up to the first
+// line it drew and erases each line before rewriting it; lines left over
+// from taller output are erased too. The cursor ends after the last line.
+// With synchronized updates the sequences go in the same single write.
+func (s *Spinner) drawLines(text string) error {
+	lines := strings.Split(text, "\n")
+	var b strings.Builder
+	if s.syncUpdates && !s.inSync {
+		b.WriteString(syncBegin)
+	}
+	if s.row > 0 {
+		b.WriteString("\033[s")
+		for i, line := range lines {
+			fmt.Fprintf(&b, "\033[%d;%dH%s", s.row+i, max(s.col, 1), line)
+		}
+		for i := len(lines); i < s.lastHeight; i++ {
+			fmt.Fprintf(&b, "\033[%d;%dH%s", s.row+i, max(s.col, 1), padding(s.lastWidth))
+		}
+		b.WriteString("\033[u")
+	} else {
+		b.WriteString(s.term.upSeq(s.lastHeight - 1))
+		for i, line := range lines {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(s.term.clearSeq() + line)
+		}
+		if extra := s.lastHeight - len(lines); extra > 0 {
+			b.WriteString(strings.Repeat("\n"+s.term.clearSeq(), extra))
+			b.WriteString(s.term.upSeq(extra))
+		}
+	}
+	if s.syncUpdates && !s.inSync {
+		b.WriteString(syncEnd)
+	}
+	s.lastHeight = len(lines)
+	_, err := fmt.Fprint(s.writer, b.String())
+	return err
+}
+
+// clearLines is clearLine for output of several lines. An inline spinner's
+// cursor is left at the start of the first line.
+func (s *Spinner) clearLines() {
+	if s.row > 0 {
+		s.drawLines(strings.Repeat(padding(s.lastWidth)+"\n", s.lastHeight-1) + padding(s.lastWidth))
+		return
+	}
+	n := s.lastHeight - 1
+	t := s.term
+	fmt.Fprint(s.writer, t.upSeq(n)+strings.Repeat(t.clearSeq()+"\n", n)+t.clearSeq()+"\r"+t.upSeq(n))
+	s.lastHeight = 1
+}
@@ -0,0 +1,130 @@
+package spinner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeFrames(t *testing.T) {
+	got := normalizeFrames([]string{"a", "bb\nc", "d\ne\nf"}, displayWidth)
+	want := []string{"a \n  \n  ", "bb\nc \n  ", "d \ne \nf "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeFrames = %q, want %q", got, want)
+	}
+	single := []string{"a", "bb"}
+	if got := normalizeFrames(single, displayWidth); &got[0] != &single[0] {
+		t.Errorf("normalizeFrames copied single-line frames")
+	}
+}
+
+func TestMultilineFrames(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	tests := []struct {
+		name  string
+		opts  []Option
+		ticks int
+		end   func(*Spinner)
+		want  string
+	}{
+		{
+			name:  "first frame",
+			ticks: 1,
+			end:   func(*Spinner) {},
+			want:  "$ build\n●\n\n  Building",
+		},
+		{
+			name:  "repaint in place",
+			ticks: 3,
+			end:   func(*Spinner) {},
+			want:  "$ build\n\n\n● Building",
+		},
+		{
+			name:  "stop",
+			ticks: 2,
+			end:   (*Spinner).Stop,
+			want:  "$ build",
+		},
+		{
+			name:  "success",
+			ticks: 2,
+			end:   func(s *Spinner) { s.Success("Built") },
+			want:  "$ build\n✔ Built",
+		},
+		{
+			name:  "persist",
+			opts:  []Option{WithClearOnStop(false)},
+			ticks: 2,
+			end:   (*Spinner).Stop,
+			want:  "$ build\n\n●\n  Building",
+		},
+		{
+			name:  "print above",
+			ticks: 2,
+			end:   func(s *Spinner) { s.LogWriter().Write([]byte("log line")) },
+			want:  "$ build\nlog line\n\n●\n  Building",
+		},
+		{
+			name:  "switch to single line",
+			ticks: 2,
+			end:   func(s *Spinner) { s.SetFrames(Line); s.tick() },
+			want:  "$ build\n- Building",
+		},
+		{
+			name:  "switch from single line",
+			opts:  []Option{WithFrames(Line)},
+			ticks: 2,
+			end:   func(s *Spinner) { s.SetFrames(TallBounce); s.tick() },
+			want:  "$ build\n●\n\n  Building",
+		},
+		{
+			name:  "screen position",
+			opts:  []Option{WithScreenPosition(3, 5)},
+			ticks: 2,
+			end:   func(*Spinner) {},
+			want:  "$ build\n\n\n    ●\n      Building",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := new(screen)
+			sc.Write([]byte("$ build\n"))
+			opts := append([]Option{WithWriter(sc), WithFrames(TallBounce), WithMessage("Building"), WithColorMode(ColorNever)}, tt.opts...)
+			var s *Spinner
+			script(opts, every(time.Millisecond, tt.ticks), func(sp *Spinner) { s = sp; tt.end(sp) })
+			if got := sc.String(); got != tt.want {
+				t.Errorf("screen:\n%s\nwant:\n%s", got, tt.want)
+			}
+			s.Stop() // spinners left running would take Println's output
+		})
+	}
+}
+
+func TestMultilineFramesPlain(t *testing.T) {
+	for _, tt := range []struct {
+		name, term string
+		tty        bool
+	}{
+		{"dumb terminal", "dumb", true},
+		{"non-terminal", "xterm", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERM", tt.term)
+			sc := new(screen)
+			s := New(WithWriter(sc), WithForceTTY(tt.tty), WithFrames(TallBounce), WithMessage("Building"))
+			s.Start()
+			s.Stop()
+			select {
+			case err := <-s.Errors():
+				if err != ErrMultilineFrames {
+					t.Errorf("Errors received %v, want ErrMultilineFrames", err)
+				}
+			default:
+				t.Error("no error reported for multiline frames without ANSI support")
+			}
+			if got := sc.String(); got != "Building" {
+				t.Errorf("screen = %q, want the message alone", got)
+			}
+		})
+	}
+}
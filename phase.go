@@ -0,0 +1,51 @@
+package spinner
+
+import "time"
+
+// Phase is a stage of a long task. Once the spinner has been running for
+// After, it switches to the phase's message, frames and color. Empty fields
+// leave the current setting alone.
+type Phase struct {
+	After   time.Duration
+	Message string
+	Frames  []string
+	Color   string
+}
+
+// WithPhases makes the spinner move through phases as time passes since
+// Start, for example from "Connecting" to "Downloading" to "Finishing". The
+// latest phase whose After has passed is the one shown. Phases should be
+// given in order of After.
+func WithPhases(phases []Phase) Option {
+	return func(s *Spinner) {
+		s.phases = phases
+		s.phase = -1
+	}
+}
+
+// applyPhase switches to the latest phase that has started, if it is not
+// the one already shown.
+func (s *Spinner) applyPhase() {
+	elapsed := s.now().Sub(s.startedAt)
+	i := -1
+	for j, p := range s.phases {
+		if p.After <= elapsed {
+			i = j
+		}
+	}
+	if i <= s.phase {
+		return
+	}
+	s.phase = i
+	p := s.phases[i]
+	if p.Message != "" {
+		s.message = s.sanitize(p.Message)
+	}
+	if p.Frames != nil {
+		s.replaceFrames(p.Frames)
+	}
+	if p.Color != "" {
+		s.color = func(FrameInfo) string { return p.Color }
+		s.userColor = nil
+	}
+}
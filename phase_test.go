@@ -0,0 +1,35 @@
+package spinner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPhases(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var lines []string
+	script([]Option{
+		WithRecorder(&lines),
+		WithFrames([]string{"a", "b"}),
+		WithPhases([]Phase{
+			{Message: "Connecting"},
+			{After: time.Second, Message: "Downloading", Frames: []string{"x", "y", "z"}},
+			{After: 3 * time.Second, Message: "Finishing", Color: Green},
+		}),
+	}, every(500*time.Millisecond, 8), (*Spinner).Stop)
+
+	want := []string{
+		"a Connecting",
+		"x Downloading",
+		"y Downloading",
+		"z Downloading",
+		"x Downloading",
+		"y Finishing",
+		"z Finishing",
+		"x Finishing",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
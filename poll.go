@@ -0,0 +1,65 @@
+package spinner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrRetry marks an error from a Poll condition as retryable: Poll keeps
+// polling after a condition returns an error for which errors.Is(err,
+// ErrRetry) is true, and gives up on any other error.
+var ErrRetry = errors.New("spinner: retry")
+
+// Poll shows a spinner with message, built from opts, while it calls cond
+// every interval until cond reports done, returns an error that is not
+// retryable (see ErrRetry), or ctx is done. cond is first called at once,
+// and the polls run on their own schedule, independent of the spinner's
+// frames; an interval that is not positive polls at the spinner's frame
+// interval. If ctx has a deadline, the time left counts down after the
+// message, as with Countdown, and like Countdown's it is not shown by a
+// spinner that does not animate.
+//
+// Poll ends the spinner with Success when cond is done and returns nil.
+// Otherwise it ends it with Fail and returns the error from cond, or
+// ctx.Err() wrapping the last retryable error, if there was one.
+func Poll(ctx context.Context, message string, interval time.Duration, cond func(ctx context.Context) (done bool, err error), opts ...Option) error {
+	s := New(append([]Option{WithMessage(message)}, opts...)...)
+	s.Start()
+	s.mu.Lock()
+	if interval <= 0 {
+		interval = max(s.interval(), time.Millisecond)
+	}
+	if deadline, ok := ctx.Deadline(); ok && s.state == stateRunning && !s.plain {
+		s.startCountdown(deadline, strings.ReplaceAll(s.message, "%", "%%")+" %s left")
+	}
+	s.mu.Unlock()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var retried error
+	for {
+		done, err := cond(ctx)
+		switch {
+		case err != nil && !errors.Is(err, ErrRetry):
+			s.Fail(fmt.Sprintf("%s: %v", message, err))
+			return err
+		case err != nil:
+			retried = err
+		case done:
+			s.Success(message)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if retried != nil {
+				err = fmt.Errorf("%w: last error: %w", err, retried)
+			}
+			s.Fail(fmt.Sprintf("%s: %v", message, err))
+			return err
+		case <-ticker.C:
+		}
+	}
+}
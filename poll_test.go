@@ -0,0 +1,77 @@
+package spinner_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestPoll(t *testing.T) {
+	errBusy := fmt.Errorf("port busy: %w", spinner.ErrRetry)
+	errGone := errors.New("host gone")
+	tests := []struct {
+		name    string
+		results []error // nil is done; the last one repeats
+		timeout time.Duration
+		every   time.Duration
+		want    string
+		wantErr []error
+		calls   int
+	}{
+		{"done", []error{errBusy, errBusy, nil}, 0, time.Millisecond, "⠋ port open\n✔ port open\n", nil, 3},
+		{"terminal", []error{errBusy, errGone}, 0, time.Millisecond, "⠋ port open\n✖ port open: host gone\n", []error{errGone}, 2},
+		{"deadline", []error{errBusy}, 50 * time.Millisecond, time.Millisecond, "⠋ port open\n✖ port open: context deadline exceeded: last error: port busy: spinner: retry\n",
+			[]error{context.DeadlineExceeded, spinner.ErrRetry}, -1},
+		{"no interval", []error{errBusy, nil}, 0, 0, "⠋ port open\n✔ port open\n", nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			var buf bytes.Buffer
+			calls := 0
+			err := spinner.Poll(ctx, "port open", tt.every, func(context.Context) (bool, error) {
+				err := tt.results[min(calls, len(tt.results)-1)]
+				calls++
+				return err == nil, err
+			}, spinner.WithTestMode(true), spinner.WithWriter(&buf))
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Poll wrote %q, want %q", got, tt.want)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Poll = %v, want nil", err)
+			}
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("Poll = %v, want an error matching %v", err, want)
+				}
+			}
+			if tt.calls >= 0 && calls != tt.calls {
+				t.Errorf("cond called %d times, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestPollCountdown(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var out lockedBuffer
+	spinner.Poll(ctx, "100% up", 5*time.Millisecond, func(context.Context) (bool, error) {
+		return false, spinner.ErrRetry
+	}, spinner.WithWriter(&out), spinner.WithForceTTY(true), spinner.WithInterval(time.Millisecond),
+		spinner.WithElapsed(true), spinner.WithDurationFormatter(func(time.Duration) string { return "took" }))
+	if got := out.String(); !strings.Contains(got, "100% up 1s left took") {
+		t.Errorf("output %q does not count down to the deadline next to the elapsed time", got)
+	}
+}
@@ -0,0 +1,58 @@
+package spinner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithScreenPosition draws the spinner at row and col of the screen,
+// counting from 1, instead of on the cursor's line. The cursor is saved
+// before and restored after every redraw, so the spinner can sit in a
+// status line of a full-screen program while output continues elsewhere.
+func WithScreenPosition(row, col int) Option {
+	return func(s *Spinner) {
+		s.row, s.col = row, col
+	}
+}
+
+// draw writes text at the start of the spinner's line: after a carriage
+// return, or at the WithScreenPosition position with the cursor saved and
+// restored around it. With CursorSaveRestore it is written from the cursor
+// with the cursor saved and restored. Text of several lines is otherwise
+// drawn by drawLines.
+func (s *Spinner) draw(text string) error {
+	if s.savesCursor() {
+		s.lastHeight = strings.Count(text, "\n") + 1
+		_, err := fmt.Fprintf(s.writer, "\033[s%s\033[u", text)
+		return err
+	}
+	if s.lastHeight > 1 || strings.Contains(text, "\n") {
+		return s.drawLines(text)
+	}
+	var err error
+	if s.row > 0 {
+		_, err = fmt.Fprintf(s.writer, "\033[s\033[%d;%dH%s\033[u", s.row, max(s.col, 1), text)
+	} else {
+		_, err = fmt.Fprintf(s.writer, "\r%s", text)
+	}
+	return err
+}
+
+// clearLine blanks what the spinner last drew, leaving an inline spinner's
+// cursor at the start of the line.
+func (s *Spinner) clearLine() {
+	if s.savesCursor() {
+		height := max(s.lastHeight, 1)
+		s.draw(strings.Repeat(padding(s.lastWidth)+"\n", height-1) + padding(s.lastWidth))
+		return
+	}
+	if s.lastHeight > 1 {
+		s.clearLines()
+		return
+	}
+	if s.row > 0 {
+		s.draw(padding(s.lastWidth))
+		return
+	}
+	fmt.Fprintf(s.writer, "\r%s\r", padding(s.lastWidth))
+}
@@ -0,0 +1,39 @@
+package spinner_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestScreenPosition(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(
+		spinner.WithWriter(&buf),
+		spinner.WithHideCursor(false),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithFrames([]string{"-"}),
+		spinner.WithMessage("syncing"),
+		spinner.WithScreenPosition(24, 3),
+	)
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.LogWriter().Write([]byte("log line"))
+	s.Stop()
+
+	out := buf.String()
+	if strings.Contains(out, "\r") {
+		t.Errorf("output %q uses carriage returns", out)
+	}
+	draw := "\033[s\033[24;3H- syncing\033[u"
+	clear := "\033[s\033[24;3H         \033[u"
+	if want := draw + "log line\n"; !strings.HasPrefix(out, want) {
+		t.Errorf("output %q does not start with %q", out, want)
+	}
+	if !strings.HasSuffix(out, clear) {
+		t.Errorf("output %q does not end by clearing the spinner in place", out)
+	}
+}
@@ -0,0 +1,49 @@
+package spinner
+
+import "strings"
+
+// prerendered holds the lines of a static spinner, rendered the first time
+// each frame is drawn and reused while the message stays the same.
+type prerendered struct {
+	message string
+	lines   []string
+	widths  []int    // -1 for a frame not rendered yet
+	writes  [][]byte // "\r" and the line, or nil if it cannot be written as is
+}
+
+// static reports whether every frame renders the same way each time it is
+// shown until the message changes: the frames and color are fixed and
+// nothing else on the line, such as progress or the elapsed time, moves.
+func (s *Spinner) static() bool {
+	return s.frameFunc == nil && s.segments == nil && s.playing == playLoop &&
+		s.colorSource != SourceFunc && s.phases == nil &&
+		s.total <= 0 && !s.determinate && !s.elapsed && s.activityLines == 0 &&
+		len(s.frames) > 0
+}
+
+// prerender returns the line for the current frame of a static spinner and
+// its width, rendering it only if it has not been rendered for the current
+// message. The bytes, if not nil, draw the line over one at least as wide.
+func (s *Spinner) prerender() (string, int, []byte) {
+	c := s.cache
+	if c == nil || c.message != s.message || len(c.lines) != len(s.frames) {
+		c = &prerendered{
+			message: s.message,
+			lines:   make([]string, len(s.frames)),
+			widths:  make([]int, len(s.frames)),
+			writes:  make([][]byte, len(s.frames)),
+		}
+		for i := range c.widths {
+			c.widths[i] = -1
+		}
+		s.cache = c
+	}
+	i := s.index
+	if c.widths[i] < 0 {
+		c.lines[i], c.widths[i] = s.render(true)
+		if s.row == 0 && !s.savesCursor() && !strings.Contains(c.lines[i], "\n") {
+			c.writes[i] = []byte("\r" + c.lines[i])
+		}
+	}
+	return c.lines[i], c.widths[i], c.writes[i]
+}
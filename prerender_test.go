@@ -0,0 +1,55 @@
+package spinner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPrerender(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var lines []string
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := New(WithWriter(&countingWriter{}), WithFrames([]string{"a", "b"}), WithRecorder(&lines))
+	s.now = clock.now
+	s.loop = manualLoop
+	s.Start()
+	defer s.Stop()
+	tick := func() {
+		clock.t = clock.t.Add(100 * time.Millisecond)
+		s.tick()
+	}
+
+	tick()
+	tick()
+	tick()
+	if s.cache == nil {
+		t.Fatal("static spinner did not prerender its frames")
+	}
+	s.UpdateMessage("msg")
+	tick()
+	s.SetFrames([]string{"c", "d"})
+	if s.cache != nil {
+		t.Error("SetFrames kept the prerendered frames")
+	}
+	tick()
+	s.SetProgress(1, 2)
+	if s.static() {
+		t.Error("spinner with progress is static")
+	}
+	tick()
+	s.SetProgress(0, 0)
+	s.mu.Lock()
+	s.color = func(FrameInfo) string { return "" }
+	s.colorSource = SourceFunc
+	s.mu.Unlock()
+	if s.static() {
+		t.Error("spinner with a color func is static")
+	}
+	tick()
+
+	want := []string{"a", "b", "a", "b msg", "c msg", "d msg 50%", "c msg"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
@@ -0,0 +1,183 @@
+package spinner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// printCurrent prints text on its own line above the current spinner, or
+// writes it to os.Stderr as it is if no spinner is running.
+func printCurrent(text string) error {
+	if s := current(); s != nil {
+		return s.printAbove(s.writer, text)
+	}
+	_, err := io.WriteString(os.Stderr, text)
+	return err
+}
+
+// printAbove writes text to w on its own line, ending it with a newline if
+// it lacks one. While the spinner is drawing, its line is cleared first and
+// redrawn below the text, so the two never mix. A spinner drawn at a fixed
+// screen position, or by a Sink, is left alone.
+func (s *Spinner) printAbove(w io.Writer, text string) error {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.idleWriterLock()
+	s.writerLock.Lock()
+	defer s.writerLock.Unlock()
+	if s.state != stateRunning || s.lastLine == "" || s.row > 0 || s.sink != nil {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+	s.beginSync()
+	defer s.endSync()
+	height := s.lastHeight
+	s.clearLine()
+	if _, err := io.WriteString(w, text); err != nil {
+		return err
+	}
+	if s.savesCursor() {
+		return s.draw(s.lastLine)
+	}
+	_, err := io.WriteString(s.writer, s.lastLine)
+	s.lastHeight = height
+	return err
+}
+
+// Println formats its arguments as fmt.Println does and prints them on
+// their own line above the spinner, on the spinner's writer, so that it can
+// replace a call such as fmt.Fprintln(os.Stderr, ...) near a running
+// spinner. While the spinner is idle the line is written directly. It is
+// safe for concurrent use.
+func (s *Spinner) Println(args ...any) error {
+	return s.printAbove(s.writer, fmt.Sprintln(args...))
+}
+
+// Printf is like Println but formats as fmt.Printf does. A newline is added
+// unless the formatted text already ends with one.
+func (s *Spinner) Printf(format string, args ...any) error {
+	return s.printAbove(s.writer, fmt.Sprintf(format, args...))
+}
+
+// Println formats its arguments as fmt.Println does and prints them on
+// their own line above the running spinner, like Spinner.Println, for code
+// that does not have the spinner at hand: calls to fmt.Println can be
+// switched to spinner.Println. When no spinner is running it writes to
+// os.Stderr. It relies on global state: the line goes above the spinner
+// that started most recently of those still running, which may not be the
+// one the caller has in mind when several run at once or a library starts
+// its own. Prefer the Spinner methods wherever the spinner is known. It is
+// safe for concurrent use.
+func Println(args ...any) error {
+	return printCurrent(fmt.Sprintln(args...))
+}
+
+// Printf is like Println but formats as fmt.Printf does. Above a running
+// spinner a newline is added unless the text already ends with one.
+func Printf(format string, args ...any) error {
+	return printCurrent(fmt.Sprintf(format, args...))
+}
+
+// Print is like Println but formats as fmt.Print does. Above a running
+// spinner a newline is added unless the text already ends with one.
+func Print(args ...any) error {
+	return printCurrent(fmt.Sprint(args...))
+}
+
+// LogWriter returns a writer for loggers such as log/slog that prints each
+// Write on its own line above the spinner. Every Write is treated as one
+// complete line, so it is safe for concurrent use by handlers that write a
+// whole record per call, as the log and log/slog packages do.
+func (s *Spinner) LogWriter() io.Writer {
+	return logWriter{s}
+}
+
+type logWriter struct {
+	s *Spinner
+}
+
+func (w logWriter) Write(p []byte) (int, error) {
+	if err := w.s.printAbove(w.s.writer, string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// SafeWriter returns a writer, suitable for log.SetOutput, that writes to
+// underlying without breaking up the spinner's line. While s is running,
+// complete lines are printed above the spinner and a trailing partial line
+// is held back until its newline arrives or s stops or is reset. While s is
+// idle, writes go straight to underlying without touching the spinner's
+// lock.
+func SafeWriter(s *Spinner, underlying io.Writer) io.Writer {
+	w := &safeWriter{s: s, w: underlying}
+	s.mu.Lock()
+	s.safeWriters = append(s.safeWriters, w)
+	s.mu.Unlock()
+	return w
+}
+
+type safeWriter struct {
+	s       *Spinner
+	w       io.Writer
+	mu      sync.Mutex
+	pending []byte // start of a line not yet ended by a newline
+}
+
+func (w *safeWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.s.active.Load() {
+		if err := w.writePending(); err != nil {
+			return 0, err
+		}
+		return w.w.Write(p)
+	}
+	w.pending = append(w.pending, p...)
+	i := bytes.LastIndexByte(w.pending, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+	lines := string(w.pending[:i+1])
+	w.pending = append(w.pending[:0], w.pending[i+1:]...)
+	if err := w.s.printAbove(w.w, lines); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writePending writes out the partial line held back while the spinner ran.
+// The caller must hold w.mu.
+func (w *safeWriter) writePending() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	if _, err := w.w.Write(w.pending); err != nil {
+		return err
+	}
+	w.pending = w.pending[:0]
+	return nil
+}
+
+// flushSafeWriters writes out the partial lines held back by s's SafeWriters
+// once s has stopped. The caller must not hold mu, which a SafeWriter takes
+// while holding its own lock.
+func (s *Spinner) flushSafeWriters() {
+	s.mu.Lock()
+	writers := s.safeWriters
+	s.mu.Unlock()
+	for _, w := range writers {
+		w.mu.Lock()
+		if !s.active.Load() {
+			w.writePending()
+		}
+		w.mu.Unlock()
+	}
+}
@@ -0,0 +1,223 @@
+package spinner_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithHideCursor(false), spinner.WithInterval(time.Millisecond))
+	logger := slog.New(slog.NewTextHandler(s.LogWriter(), nil))
+	s.Start()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				logger.Info("step", "worker", i, "n", j)
+				time.Sleep(time.Millisecond)
+			}
+		}(i)
+	}
+	wg.Wait()
+	s.Stop()
+
+	lines := strings.Split(buf.String(), "\n")
+	if got := len(lines) - 1; got != 200 {
+		t.Fatalf("got %d log lines, want 200", got)
+	}
+	for _, line := range lines[:len(lines)-1] {
+		// Anything before the last carriage return is cleared spinner output.
+		rec := line[strings.LastIndex(line, "\r")+1:]
+		if !strings.HasPrefix(rec, "time=") || !strings.Contains(rec, " msg=step worker=") {
+			t.Errorf("log line mixed with spinner output: %q", line)
+		}
+	}
+}
+
+func TestPrintf(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithHideCursor(false), spinner.WithInterval(time.Millisecond))
+	s.Start()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				if j%2 == 0 {
+					s.Printf("worker %d step %d", i, j)
+				} else {
+					s.Println("worker", i, "step", j)
+				}
+				time.Sleep(time.Millisecond / 2)
+			}
+		}(i)
+	}
+	wg.Wait()
+	s.Stop()
+
+	seen := make(map[string]bool)
+	lines := strings.Split(buf.String(), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		seen[line[strings.LastIndex(line, "\r")+1:]] = true
+	}
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 25; j++ {
+			if want := fmt.Sprintf("worker %d step %d", i, j); !seen[want] {
+				t.Errorf("missing or interleaved line %q", want)
+			}
+		}
+	}
+	if got := len(lines) - 1; got != 200 {
+		t.Errorf("got %d lines, want 200", got)
+	}
+}
+
+func TestPrintfIdle(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf))
+	s.Printf("%d files", 3)
+	s.Println("done")
+	if got := buf.String(); got != "3 files\ndone\n" {
+		t.Errorf("idle Printf and Println wrote %q", got)
+	}
+}
+
+func TestLogWriterIdle(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf))
+	s.LogWriter().Write([]byte("hello"))
+	if got := buf.String(); got != "hello\n" {
+		t.Errorf("idle LogWriter wrote %q, want %q", got, "hello\n")
+	}
+}
+
+func TestSafeWriter(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithHideCursor(false), spinner.WithInterval(time.Millisecond))
+	w := spinner.SafeWriter(s, &buf)
+	logger := log.New(w, "", 0)
+	s.Start()
+	for i := 0; i < 50; i++ {
+		logger.Printf("line %d", i)
+		time.Sleep(time.Millisecond / 2)
+	}
+	w.Write([]byte("first\nsec"))
+	time.Sleep(2 * time.Millisecond)
+	w.Write([]byte("ond\n"))
+	s.Stop()
+
+	var got []string
+	lines := strings.Split(buf.String(), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		got = append(got, line[strings.LastIndex(line, "\r")+1:])
+	}
+	if len(got) != 52 {
+		t.Fatalf("got %d lines, want 52: %q", len(got), got)
+	}
+	for i, line := range got[:50] {
+		if want := fmt.Sprintf("line %d", i); line != want {
+			t.Errorf("line %d = %q, want %q", i, line, want)
+		}
+	}
+	if got[50] != "first" || got[51] != "second" {
+		t.Errorf("multi-line write gave %q, want [first second]", got[50:])
+	}
+}
+
+func TestSafeWriterIdle(t *testing.T) {
+	var spin, out bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&spin))
+	w := spinner.SafeWriter(s, &out)
+	w.Write([]byte("no newline"))
+	w.Write([]byte(" yet\n"))
+	if got := out.String(); got != "no newline yet\n" {
+		t.Errorf("idle SafeWriter wrote %q, want %q", got, "no newline yet\n")
+	}
+	if spin.Len() != 0 {
+		t.Errorf("idle SafeWriter touched the spinner's writer: %q", spin.String())
+	}
+}
+
+func TestSafeWriterFlush(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		end  func(*spinner.Spinner)
+	}{
+		{"stop", (*spinner.Spinner).Stop},
+		{"reset", func(s *spinner.Spinner) { s.Reset() }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var spin, out bytes.Buffer
+			s := spinner.New(spinner.WithWriter(&spin), spinner.WithInterval(time.Hour))
+			w := spinner.SafeWriter(s, &out)
+			s.Start()
+			w.Write([]byte("done\nno newline"))
+			if got := out.String(); got != "done\n" {
+				t.Errorf("running SafeWriter wrote %q, want the complete line only", got)
+			}
+			tt.end(s)
+			if got, want := out.String(), "done\nno newline"; got != want {
+				t.Errorf("after %s SafeWriter wrote %q, want %q", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestPackagePrint(t *testing.T) {
+	var a, b bytes.Buffer
+	sa := spinner.New(spinner.WithWriter(&a), spinner.WithHideCursor(false), spinner.WithInterval(time.Millisecond))
+	sb := spinner.New(spinner.WithWriter(&b), spinner.WithHideCursor(false), spinner.WithInterval(time.Millisecond))
+	sa.Start()
+	spinner.Println("one", 1)
+	sb.Start()
+	spinner.Printf("two %d", 2)
+	sb.Stop()
+	spinner.Print("three")
+	sa.Stop()
+
+	for _, tt := range []struct {
+		name string
+		buf  *bytes.Buffer
+		want []string
+	}{
+		{"first", &a, []string{"one 1\n", "three\n"}},
+		{"second", &b, []string{"two 2\n"}},
+	} {
+		out := tt.buf.String()
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s spinner's output %q lacks %q", tt.name, out, want)
+			}
+		}
+	}
+	if strings.Contains(a.String(), "two") || strings.Contains(b.String(), "one") || strings.Contains(b.String(), "three") {
+		t.Errorf("line printed above the wrong spinner: %q, %q", a.String(), b.String())
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	stderr := os.Stderr
+	os.Stderr = f
+	spinner.Print("no ", "spinner")
+	os.Stderr = stderr
+	if got, _ := os.ReadFile(f.Name()); string(got) != "no spinner" {
+		t.Errorf("with no spinner running, Print wrote %q to os.Stderr, want %q", got, "no spinner")
+	}
+}
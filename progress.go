@@ -0,0 +1,246 @@
+package spinner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const oscProgressClear = "\033]9;4;0;\a"
+
+// SetProgress sets the progress shown after the message, as a percentage
+// unless WithProgressFormat says otherwise. A total of zero or less hides it.
+func (s *Spinner) SetProgress(current, total int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pendingProgress.Store(nil)
+	s.setProgress(current, total)
+	if s.testMode.Load() && s.plain && s.state == stateRunning {
+		s.writerLock.Lock()
+		defer s.writerLock.Unlock()
+		s.testFrame()
+	}
+}
+
+// WithProgressBar makes the spinner animate as usual until SetProgress
+// first reports a total, and from then on draw a progress bar width columns
+// wide in place of the frame. It suits tasks whose size is only known once
+// they are under way, such as a download waiting for its headers.
+func WithProgressBar(width int) Option {
+	return func(s *Spinner) {
+		s.barWidth = width
+	}
+}
+
+// WithProgressFormat sets the function that renders progress in place of
+// the default percentage. It is called on every frame while SetProgress has
+// set a total, with the fraction done from 0 to 1 and the time since Start,
+// which is enough to show an ETA or a rate.
+func WithProgressFormat(format func(fraction float64, elapsed time.Duration) string) Option {
+	return func(s *Spinner) {
+		s.progressFormat = format
+	}
+}
+
+// WithPercentFormatter sets the function that renders progress as a
+// percentage, in place of FormatPercent, for another style such as "42 %",
+// "0.42" or "21/50". WithProgressFormat, if set, takes precedence.
+func WithPercentFormatter(format func(current, total int64) string) Option {
+	return func(s *Spinner) {
+		s.percentFormat = format
+	}
+}
+
+// WithDurationFormatter sets the function that renders the elapsed time
+// shown by WithElapsed, in place of FormatDuration.
+func WithDurationFormatter(format func(time.Duration) string) Option {
+	return func(s *Spinner) {
+		s.durationFormat = format
+	}
+}
+
+// A Formatter defines how numbers in the spinner's line look, so that one
+// value can give every spinner in a program a consistent style. Nil fields
+// keep the spinner's formatter.
+type Formatter struct {
+	Percent  func(current, total int64) string
+	Duration func(time.Duration) string
+}
+
+// WithFormatter sets the spinner's formatters from f.
+func WithFormatter(f Formatter) Option {
+	return func(s *Spinner) {
+		if f.Percent != nil {
+			s.percentFormat = f.Percent
+		}
+		if f.Duration != nil {
+			s.durationFormat = f.Duration
+		}
+	}
+}
+
+// FormatPercent renders current/total as a whole percentage, such as
+// "42%". It is the default percent formatter.
+func FormatPercent(current, total int64) string {
+	return fmt.Sprintf("%d%%", percent(current, total))
+}
+
+// FormatDuration renders d truncated to the second, such as "1m5s". It is
+// the default duration formatter.
+func FormatDuration(d time.Duration) string {
+	return d.Truncate(time.Second).String()
+}
+
+// fraction returns current/total clamped to [0, 1].
+func fraction(current, total int64) float64 {
+	if total <= 0 || current <= 0 {
+		return 0
+	}
+	return min(float64(current)/float64(total), 1)
+}
+
+// ProgressFrameFunc returns a frame func, for WithFrameInfoFunc, that draws
+// the spinner's progress as a bar width columns wide between brackets, such
+// as "[█████▌    ]". Partial cells are drawn in eighths, so the bar grows
+// smoothly. Until SetProgress reports a total the bar is empty.
+func ProgressFrameFunc(width int) func(FrameInfo) string {
+	return func(f FrameInfo) string {
+		eighths := int(fraction(f.Current, f.Total) * float64(width*8))
+		full, part := eighths/8, eighths%8
+		bar := strings.Repeat("█", full)
+		if part > 0 {
+			bar += string(partialBlocks[part])
+			full++
+		}
+		return "[" + bar + padding(width-full) + "]"
+	}
+}
+
+// partialBlocks holds the cells filled by n eighths from the left.
+var partialBlocks = []rune(" ▏▎▍▌▋▊▉")
+
+// progressBar returns a bar width columns wide filled to current/total.
+func progressBar(current, total int64, width int) string {
+	filled := int(percent(current, total) * int64(width) / 100)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// WithOSCProgress makes the spinner report its progress to the terminal with
+// OSC 9;4 sequences, which terminals such as Windows Terminal, ConEmu and
+// iTerm2 show as a taskbar or tab indicator. The indicator is cleared on
+// Stop. Nothing is sent unless the writer is a terminal.
+func WithOSCProgress(enable bool) Option {
+	return func(s *Spinner) {
+		s.oscProgress = enable
+	}
+}
+
+// writeOSCProgress sends the current percentage if it changed since the
+// last report.
+func (s *Spinner) writeOSCProgress() {
+	if !s.oscProgress || !s.tty || s.total <= 0 {
+		return
+	}
+	if pct := percent(s.current, s.total); pct != s.oscPercent {
+		fmt.Fprintf(s.writer, "\033]9;4;1;%d\a", pct)
+		s.oscPercent = pct
+	}
+}
+
+// Progress is a progress report consumed by WatchProgress.
+type Progress struct {
+	Current int64
+	Total   int64
+	Message string
+	Err     error
+}
+
+// setProgress records progress. The caller must hold mu.
+func (s *Spinner) setProgress(current, total int64) {
+	s.current, s.total = current, total
+	if total > 0 && s.barWidth > 0 {
+		s.determinate = true
+	}
+}
+
+// WatchProgress starts the spinner and renders each Progress received on ch.
+// Values are consumed as they arrive and stored, like UpdateMessage's
+// messages, without taking the spinner's lock, and the next frame shows
+// the most recent one, so senders are never held up by rendering or by a
+// slow writer. The spinner finishes with Success when ch is closed, or with
+// Fail as soon as a Progress carrying an Err arrives. Nothing is received
+// from ch after that, so a sender must not send after an error.
+func (s *Spinner) WatchProgress(ch <-chan Progress) {
+	s.Start()
+	go func() {
+		for p := range ch {
+			if p.Err != nil {
+				s.Fail(p.Err.Error())
+				return
+			}
+			s.pendingProgress.Store(&[2]int64{p.Current, p.Total})
+			if p.Message != "" {
+				s.UpdateMessage(p.Message)
+			} else if s.testMode.Load() {
+				s.testStep()
+			}
+		}
+		s.Success("")
+	}()
+}
+
+// percent returns current as a percentage of total, clamped to [0, 100].
+func percent(current, total int64) int64 {
+	if total <= 0 || current <= 0 {
+		return 0
+	}
+	if current >= total {
+		return 100
+	}
+	return current * 100 / total
+}
+
+// etaSmoothing is the weight of the newest rate sample in ETA's average.
+const etaSmoothing = 0.3
+
+// ETA returns a function that estimates the time left from successive
+// progress fractions, for use in a WithProgressFormat func. It measures how
+// fast the fraction grows between calls and smooths that rate, so short
+// bursts and stalls move the estimate gradually. It returns zero while the
+// estimate is unknown: before progress starts, on the first call, and after
+// progress goes backwards, which starts the measurement over. It also
+// returns zero once the fraction reaches one.
+func ETA() func(fraction float64) time.Duration {
+	return eta(time.Now)
+}
+
+func eta(now func() time.Time) func(float64) time.Duration {
+	var (
+		lastT time.Time
+		lastF float64
+		rate  float64 // fraction per second
+	)
+	return func(f float64) time.Duration {
+		t := now()
+		if f <= 0 || lastT.IsZero() || f < lastF {
+			lastT, lastF, rate = t, max(f, 0), 0
+			return 0
+		}
+		if f >= 1 {
+			return 0
+		}
+		if dt := t.Sub(lastT).Seconds(); f > lastF && dt > 0 {
+			r := (f - lastF) / dt
+			if rate == 0 {
+				rate = r
+			} else {
+				rate = etaSmoothing*r + (1-etaSmoothing)*rate
+			}
+			lastT, lastF = t, f
+		}
+		if rate <= 0 {
+			return 0
+		}
+		return time.Duration((1 - f) / rate * float64(time.Second))
+	}
+}
@@ -0,0 +1,301 @@
+package spinner_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestWatchProgressBursty(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithHideCursor(false), spinner.WithColorMode(spinner.ColorAlways), spinner.WithInterval(time.Millisecond))
+	ch := make(chan spinner.Progress)
+	s.WatchProgress(ch)
+
+	start := time.Now()
+	for burst := 0; burst < 5; burst++ {
+		for i := 1; i <= 1000; i++ {
+			ch <- spinner.Progress{Current: int64(burst*1000 + i), Total: 5000, Message: "copying"}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(ch)
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("sending took %v, producer was held up", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "copying ") || !strings.Contains(out, "%") {
+		t.Errorf("output %q does not show message and percentage", out)
+	}
+	if want := spinner.Green + "✔" + spinner.Reset + " copying\n"; !strings.HasSuffix(out, want) {
+		t.Errorf("output %q does not end with %q", out, want)
+	}
+}
+
+func TestWatchProgressError(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithHideCursor(false), spinner.WithColorMode(spinner.ColorAlways))
+	ch := make(chan spinner.Progress)
+	s.WatchProgress(ch)
+	ch <- spinner.Progress{Current: 1, Total: 2, Message: "uploading"}
+	ch <- spinner.Progress{Err: errors.New("connection reset")}
+	// ch is left open: WatchProgress stops receiving after an error.
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := spinner.Red + "✖" + spinner.Reset + " connection reset\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("output %q does not end with %q", buf.String(), want)
+	}
+}
+
+// slowWriter takes a while over every write.
+type slowWriter struct{ lockedBuffer }
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(100 * time.Millisecond)
+	return w.lockedBuffer.Write(p)
+}
+
+func TestWatchProgressSlowWriter(t *testing.T) {
+	var w slowWriter
+	s := spinner.New(spinner.WithWriter(&w), spinner.WithHideCursor(false), spinner.WithInterval(time.Millisecond))
+	ch := make(chan spinner.Progress)
+	s.WatchProgress(ch)
+	time.Sleep(5 * time.Millisecond) // let a tick start writing
+	start := time.Now()
+	for i := 1; i <= 1000; i++ {
+		ch <- spinner.Progress{Current: int64(i), Total: 1000}
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Errorf("sending took %v, producer was held up by the writer", d)
+	}
+	time.Sleep(300 * time.Millisecond)
+	close(ch)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if out := w.String(); !strings.Contains(out, "100%") {
+		t.Errorf("output %q does not show the last progress", out)
+	}
+}
+
+func TestOSCProgress(t *testing.T) {
+	run := func(tty bool, opts ...spinner.Option) string {
+		var buf bytes.Buffer
+		s := spinner.New(append([]spinner.Option{spinner.WithWriter(&buf), spinner.WithHideCursor(false), spinner.WithInterval(5 * time.Millisecond)}, opts...)...)
+		spinner.SetTTY(s, tty)
+		s.SetProgress(1, 4)
+		s.Start()
+		time.Sleep(30 * time.Millisecond)
+		s.SetProgress(3, 4)
+		time.Sleep(30 * time.Millisecond)
+		s.Stop()
+		return buf.String()
+	}
+
+	out := run(true, spinner.WithOSCProgress(true))
+	if n := strings.Count(out, "\033]9;4;1;25\a"); n != 1 {
+		t.Errorf("25%% reported %d times, want once: %q", n, out)
+	}
+	if n := strings.Count(out, "\033]9;4;1;75\a"); n != 1 {
+		t.Errorf("75%% reported %d times, want once: %q", n, out)
+	}
+	if !strings.HasSuffix(out, "\033]9;4;0;\a") {
+		t.Errorf("output %q does not end by clearing the indicator", out)
+	}
+
+	for name, out := range map[string]string{
+		"not a terminal": run(false, spinner.WithOSCProgress(true)),
+		"disabled":       run(true),
+	} {
+		if strings.Contains(out, "\033]9;4;") {
+			t.Errorf("%s: output %q contains OSC progress", name, out)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	var lines []string
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithFrames([]string{"-", "+"}),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithProgressBar(10),
+		spinner.WithRecorder(&lines),
+	)
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.SetProgress(3, 10)
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+
+	i := 0
+	for i < len(lines) && (lines[i] == "-" || lines[i] == "+") {
+		i++
+	}
+	if i == 0 {
+		t.Errorf("lines = %q, want frames before the first SetProgress", lines)
+	}
+	if i == len(lines) {
+		t.Fatalf("lines = %q, no bar after SetProgress", lines)
+	}
+	for _, line := range lines[i:] {
+		if want := "███░░░░░░░ 30%"; line != want {
+			t.Errorf("line %q after SetProgress, want %q", line, want)
+		}
+	}
+}
+
+func TestProgressFormat(t *testing.T) {
+	var lines []string
+	var elapsed time.Duration
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithFrames([]string{"-"}),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithRecorder(&lines),
+		spinner.WithProgressFormat(func(f float64, d time.Duration) string {
+			elapsed = d
+			return fmt.Sprintf("%.2f done", f)
+		}),
+	)
+	s.SetProgress(1, 8)
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+	if want := "- 0.12 done"; len(lines) == 0 || lines[len(lines)-1] != want {
+		t.Errorf("lines = %q, want last %q", lines, want)
+	}
+	if elapsed <= 0 || elapsed > time.Second {
+		t.Errorf("format got elapsed %v", elapsed)
+	}
+}
+
+func TestProgressFrameFunc(t *testing.T) {
+	bar := spinner.ProgressFrameFunc(10)
+	for _, tt := range []struct {
+		current, total int64
+		want           string
+	}{
+		{0, 0, "[          ]"},
+		{0, 100, "[          ]"},
+		{55, 100, "[█████▌    ]"},
+		{99, 100, "[█████████▉]"},
+		{100, 100, "[██████████]"},
+		{150, 100, "[██████████]"},
+	} {
+		if got := bar(spinner.FrameInfo{Current: tt.current, Total: tt.total}); got != tt.want {
+			t.Errorf("%d/%d: got %q, want %q", tt.current, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestFrameFunc(t *testing.T) {
+	frames := []string{"a", "bbb", "c"}
+	var n int
+	var buf bytes.Buffer
+	var lines []string
+	s := spinner.New(
+		spinner.WithWriter(&buf),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithHideCursor(false),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithRecorder(&lines),
+		spinner.WithFrameFunc(func() string {
+			f := frames[min(n, len(frames)-1)]
+			n++
+			return f
+		}),
+	)
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+	if len(lines) < 3 || lines[0] != "a" || lines[1] != "bbb" || lines[2] != "c" {
+		t.Errorf("lines = %q, want the frames from the func", lines)
+	}
+	if !strings.Contains(buf.String(), "\rbbb\rc  ") {
+		t.Errorf("output %q does not clear the longer frame", buf.String())
+	}
+}
+
+func TestPercentFormatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		format func(current, total int64) string
+		want   string
+	}{
+		{"default", spinner.FormatPercent, "- 42%"},
+		{"french", func(c, t int64) string { return spinner.FormatPercent(c, t)[:2] + " %" }, "- 42 %"},
+		{"decimal", func(c, t int64) string { return fmt.Sprintf("%.2f", float64(c)/float64(t)) }, "- 0.42"},
+		{"fraction", func(c, t int64) string { return fmt.Sprintf("%d/%d", c, t) }, "- 21/50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			s := spinner.New(
+				spinner.WithWriter(&bytes.Buffer{}),
+				spinner.WithColorMode(spinner.ColorNever),
+				spinner.WithFrames([]string{"-"}),
+				spinner.WithInterval(time.Millisecond),
+				spinner.WithRecorder(&lines),
+				spinner.WithPercentFormatter(tt.format),
+			)
+			s.SetProgress(21, 50)
+			s.Start()
+			time.Sleep(10 * time.Millisecond)
+			s.Stop()
+			if len(lines) == 0 || lines[0] != tt.want {
+				t.Errorf("lines = %q, want %q", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	f := spinner.Formatter{
+		Percent:  func(c, t int64) string { return fmt.Sprintf("%d of %d", c, t) },
+		Duration: func(d time.Duration) string { return fmt.Sprintf("%.0fms", d.Seconds()*1000) },
+	}
+	clock := time.Unix(0, 0)
+	var lines []string
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithFrames([]string{"-"}),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithRecorder(&lines),
+		spinner.WithElapsed(true),
+		spinner.WithFormatter(f),
+		spinner.WithFormatter(spinner.Formatter{}),
+	)
+	spinner.SetClock(s, func() time.Time { return clock })
+	s.SetProgress(3, 4)
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+	if want := "- 3 of 4 0ms"; len(lines) == 0 || lines[0] != want {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	if got := spinner.FormatDuration(65*time.Second + 300*time.Millisecond); got != "1m5s" {
+		t.Errorf("FormatDuration = %q, want 1m5s", got)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build race
+
+package spinner
+
+const raceEnabled = true
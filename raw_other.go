@@ -0,0 +1,19 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package spinner
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw is not supported on this system, so the terminal is never
+// queried.
+func makeRaw(f *os.File) (restore func(), err error) {
+	return nil, errors.New("spinner: raw terminal mode not supported")
+}
+
+// isForeground always reports false, as the terminal is never queried.
+func isForeground(f *os.File) bool {
+	return false
+}
@@ -0,0 +1,62 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package spinner
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw turns off line buffering and echo on the terminal f, so that a
+// reply to a query can be read as it arrives without showing up on screen.
+// It returns a function that restores the previous settings. The file
+// descriptor is reached through SyscallConn, which keeps f usable with read
+// deadlines.
+func makeRaw(f *os.File) (restore func(), err error) {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	ioctl := func(req uintptr, t *syscall.Termios) error {
+		var errno syscall.Errno
+		err := conn.Control(func(fd uintptr) {
+			_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(unsafe.Pointer(t)))
+		})
+		if err != nil {
+			return err
+		}
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	}
+	var old syscall.Termios
+	if err := ioctl(ioctlGetTermios, &old); err != nil {
+		return nil, err
+	}
+	raw := old
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO
+	if err := ioctl(ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	// Restoring the settings also discards input not read yet, such as a
+	// late reply to a query.
+	return func() { ioctl(ioctlFlushSetTermios, &old) }, nil
+}
+
+// isForeground reports whether the process is in the foreground process
+// group of the terminal f. Changing the settings of a terminal from the
+// background stops the process with SIGTTOU.
+func isForeground(f *os.File) bool {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return false
+	}
+	var pgrp int32
+	var errno syscall.Errno
+	err = conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGPGRP, uintptr(unsafe.Pointer(&pgrp)))
+	})
+	return err == nil && errno == 0 && int(pgrp) == syscall.Getpgrp()
+}
@@ -0,0 +1,53 @@
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNilWriter is reported by NewWithError when WithWriter is given a nil
+// writer.
+var ErrNilWriter = errors.New("spinner: nil writer")
+
+// RenderState describes what a spinner shows on its writer, so that a
+// program can tell why no animation appears.
+type RenderState int
+
+const (
+	// Animating spinners redraw their frames in place.
+	Animating RenderState = iota
+	// PlainText spinners, on a dumb terminal, print their message once
+	// without frames or escape sequences.
+	PlainText
+	// Suppressed spinners show nothing while running: their writer is
+	// io.Discard, SPINNER_DISABLED turned them off, or WithQuietUntilError
+	// holds their output back.
+	Suppressed
+)
+
+func (r RenderState) String() string {
+	switch r {
+	case Animating:
+		return "animating"
+	case PlainText:
+		return "plain text"
+	case Suppressed:
+		return "suppressed"
+	}
+	return fmt.Sprintf("RenderState(%d)", int(r))
+}
+
+// RenderState reports how the spinner renders with its current writer and
+// environment.
+func (s *Spinner) RenderState() RenderState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.disabled || s.writer == io.Discard || s.quiet && s.plain:
+		return Suppressed
+	case s.plain:
+		return PlainText
+	}
+	return Animating
+}
@@ -0,0 +1,56 @@
+package spinner_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tmc/spinner"
+)
+
+func TestRenderState(t *testing.T) {
+	tests := []struct {
+		name string
+		term string
+		opts []spinner.Option
+		want spinner.RenderState
+	}{
+		{"stderr", "xterm", nil, spinner.Animating},
+		{"buffer", "xterm", []spinner.Option{spinner.WithWriter(&bytes.Buffer{})}, spinner.Animating},
+		{"file", "xterm", []spinner.Option{spinner.WithWriter(os.Stdout)}, spinner.Animating},
+		{"dumb", "dumb", nil, spinner.PlainText},
+		{"dumb forced", "dumb", []spinner.Option{spinner.WithForceAnimation(true)}, spinner.Animating},
+		{"discard", "xterm", []spinner.Option{spinner.WithWriter(io.Discard)}, spinner.Suppressed},
+		{"discard dumb", "dumb", []spinner.Option{spinner.WithWriter(io.Discard)}, spinner.Suppressed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERM", tt.term)
+			if got := spinner.New(tt.opts...).RenderState(); got != tt.want {
+				t.Errorf("RenderState = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("SPINNER_DISABLED", "true")
+		if got := spinner.New(spinner.WithEnvOverrides()).RenderState(); got != spinner.Suppressed {
+			t.Errorf("RenderState = %v, want %v", got, spinner.Suppressed)
+		}
+	})
+}
+
+func TestNilWriter(t *testing.T) {
+	if _, err := spinner.NewWithError(spinner.WithWriter(nil)); !errors.Is(err, spinner.ErrNilWriter) {
+		t.Errorf("NewWithError(WithWriter(nil)) error = %v, want ErrNilWriter", err)
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "io.Discard") {
+			t.Errorf("New(WithWriter(nil)) panic = %v, want a message pointing to io.Discard", r)
+		}
+	}()
+	spinner.New(spinner.WithWriter(nil))
+}
@@ -0,0 +1,118 @@
+package spinner
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WithRawMessages turns off message sanitizing. By default messages, both
+// the spinner's and those passed to its finishers, such as Success, have
+// line breaks replaced and other control characters and escape sequences
+// removed, since they would break the single redrawn line. Callers that
+// embed their own escape sequences, such as colors, can opt out with
+// WithRawMessages(true).
+func WithRawMessages(raw bool) Option {
+	return func(s *Spinner) {
+		s.rawMessages = raw
+	}
+}
+
+// WithNewlineReplacement sets the text that replaces line breaks in
+// messages. It defaults to a single space.
+func WithNewlineReplacement(repl string) Option {
+	return func(s *Spinner) {
+		s.newline = repl
+	}
+}
+
+// sanitize returns msg made safe for the spinner's line, unless raw
+// messages were asked for.
+func (s *Spinner) sanitize(msg string) string {
+	if s.rawMessages {
+		return msg
+	}
+	return sanitizeMessage(msg, s.newline)
+}
+
+// sanitizeMessage replaces each line break in msg (\n, \r or \r\n) with
+// newline and drops other C0 and C1 control characters along with any CSI,
+// OSC or other escape sequences they introduce.
+func sanitizeMessage(msg, newline string) string {
+	if !hasControl(msg) {
+		return msg
+	}
+	var b strings.Builder
+	for i := 0; i < len(msg); {
+		r, n := utf8.DecodeRuneInString(msg[i:])
+		switch {
+		case r == '\r' || r == '\n':
+			b.WriteString(newline)
+			if r == '\r' && strings.HasPrefix(msg[i+1:], "\n") {
+				n++
+			}
+		case r == '\033' || r == 0x9b || r == 0x9d:
+			n = escapeLen(msg[i:])
+		case r < 0x20 || r == 0x7f || r >= 0x80 && r < 0xa0:
+		default:
+			b.WriteString(msg[i : i+n])
+		}
+		i += n
+	}
+	return b.String()
+}
+
+func hasControl(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r >= 0x7f && r < 0xa0 {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeLen returns the length of the escape sequence at the start of s,
+// which begins with ESC or a C1 CSI or OSC introducer. An unterminated
+// sequence runs to the end of s.
+func escapeLen(s string) int {
+	r, i := utf8.DecodeRuneInString(s)
+	kind := r
+	if r == '\033' {
+		if len(s) < 2 {
+			return len(s)
+		}
+		switch s[1] {
+		case '[':
+			kind = 0x9b
+		case ']':
+			kind = 0x9d
+		default:
+			// Two-character sequence such as ESC 7 or ESC c.
+			_, n := utf8.DecodeRuneInString(s[1:])
+			return 1 + n
+		}
+		i = 2
+	}
+	if kind == 0x9b {
+		// CSI: parameter and intermediate bytes, then a final byte.
+		for ; i < len(s); i++ {
+			c := s[i]
+			if c >= 0x40 && c <= 0x7e {
+				return i + 1
+			}
+			if c < 0x20 || c > 0x7e {
+				return i
+			}
+		}
+		return len(s)
+	}
+	// OSC: ends with BEL or ST (ESC \).
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == '\a':
+			return i + 1
+		case s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\':
+			return i + 2
+		}
+	}
+	return len(s)
+}
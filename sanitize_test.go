@@ -0,0 +1,67 @@
+package spinner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSanitizeMessage(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"héllo 世界", "héllo 世界"},
+		{"line one\nline two", "line one␤line two"},
+		{"crlf\r\nend\rcr", "crlf␤end␤cr"},
+		{"tab\there\x00\x07\x08", "tabhere"},
+		{"\033[31mred\033[0m", "red"},
+		{"\033[2K\033[1Gclear", "clear"},
+		{"\033[?25lhidden cursor", "hidden cursor"},
+		{"\033]0;title\aafter", "after"},
+		{"\033]8;;http://x\033\\link\033]8;;\033\\", "link"},
+		{"\u009b31mc1 csi", "c1 csi"},
+		{"esc\0337save", "escsave"},
+		{"unterminated\033[31", "unterminated"},
+		{"dangling\033", "dangling"},
+		{"csi broken by\033[3\nnewline", "csi broken by␤newline"},
+		{"del\x7f", "del"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeMessage(tt.in, "␤"); got != tt.want {
+			t.Errorf("sanitizeMessage(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizedOutput(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	hostile := "fetch failed:\n\033[2J\033]0;pwned\a\rretrying\x1b[1A"
+	for _, raw := range []bool{false, true} {
+		var buf bytes.Buffer
+		var lines []string
+		s := New(WithWriter(&buf), WithHideCursor(false), WithColorMode(ColorNever),
+			WithMessage(hostile), WithRecorder(&lines), WithRawMessages(raw))
+		s.Start()
+		time.Sleep(30 * time.Millisecond)
+		s.Fail(hostile)
+
+		out := buf.String()
+		if raw {
+			if !strings.Contains(out, hostile) {
+				t.Errorf("raw output %q does not contain the message unchanged", out)
+			}
+			continue
+		}
+		if want := "⠋ fetch failed:  retrying"; len(lines) == 0 || lines[0] != want {
+			t.Errorf("lines = %q, want first %q", lines, want)
+		}
+		if !strings.HasSuffix(out, "✖ fetch failed:  retrying\n") {
+			t.Errorf("output %q does not end with the sanitized failure", out)
+		}
+		if strings.Count(out, "\n") != 1 || strings.Contains(out, "\033") {
+			t.Errorf("output %q contains line breaks or escapes from the message", out)
+		}
+	}
+}
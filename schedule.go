@@ -0,0 +1,55 @@
+package spinner
+
+import "time"
+
+// MessageAt is a message shown once the spinner has run for After.
+type MessageAt struct {
+	After   time.Duration
+	Message string
+}
+
+// WithMessageSchedule makes the spinner's message change as a wait drags
+// on, for example to "still working…" after 10s and "this can take a few
+// minutes…" after 30s. Each tick shows the latest entry whose After has
+// passed since Start. Entries should be given in order of After. A message
+// set with UpdateMessage replaces the scheduled one until the next entry
+// is due, or for the rest of the run with WithScheduleOverride(true).
+func WithMessageSchedule(entries []MessageAt) Option {
+	return func(s *Spinner) {
+		s.schedule = entries
+		s.scheduled = -1
+	}
+}
+
+// WithScheduleOverride sets whether a message set with UpdateMessage stops
+// the WithMessageSchedule schedule for the rest of the run, rather than
+// until the next entry is due.
+func WithScheduleOverride(permanent bool) Option {
+	return func(s *Spinner) {
+		s.overridePermanent = permanent
+	}
+}
+
+// applySchedule switches to the latest scheduled message that is due, if
+// it is not the one already shown. The message it first replaces in a run
+// is kept for Start to put back.
+func (s *Spinner) applySchedule() {
+	if s.overridden && s.overridePermanent {
+		return
+	}
+	elapsed := s.now().Sub(s.startedAt)
+	i := -1
+	for j, e := range s.schedule {
+		if e.After <= elapsed {
+			i = j
+		}
+	}
+	if i <= s.scheduled {
+		return
+	}
+	if s.scheduled < 0 {
+		s.baseMessage = s.message
+	}
+	s.scheduled = i
+	s.message = s.sanitize(s.schedule[i].Message)
+}
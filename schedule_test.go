@@ -0,0 +1,78 @@
+package spinner
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMessageSchedule(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	schedule := WithMessageSchedule([]MessageAt{
+		{0, "working"},
+		{10 * time.Second, "still working"},
+		{30 * time.Second, "almost there"},
+	})
+	steps := []step{
+		{advance: time.Second},
+		{advance: 10 * time.Second},
+		{advance: 5 * time.Second, message: "copying"},
+		{advance: 5 * time.Second},
+		{advance: 10 * time.Second},
+	}
+	tests := []struct {
+		name      string
+		permanent bool
+		want      []string
+	}{
+		{"until next entry", false, []string{"working", "still working", "copying", "copying", "almost there"}},
+		{"permanent", true, []string{"working", "still working", "copying", "copying", "copying"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+			s := New(WithWriter(io.Discard), WithFrames([]string{"-"}), WithRecorder(&lines), schedule, WithScheduleOverride(tt.permanent))
+			s.now = clock.now
+			s.loop = manualLoop
+			s.Start()
+			for _, st := range steps {
+				clock.t = clock.t.Add(st.advance)
+				if st.message != "" {
+					s.UpdateMessage(st.message)
+				}
+				s.tick()
+			}
+			s.Stop()
+			var got []string
+			for _, l := range lines {
+				got = append(got, l[len("- "):])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageScheduleRestart(t *testing.T) {
+	var lines []string
+	clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := New(WithWriter(io.Discard), WithFrames([]string{"-"}), WithRecorder(&lines), WithMessage("waiting"),
+		WithMessageSchedule([]MessageAt{{10 * time.Second, "still waiting"}}))
+	s.now = clock.now
+	s.loop = manualLoop
+	for range 2 {
+		s.Start()
+		for _, d := range []time.Duration{time.Second, 10 * time.Second} {
+			clock.t = clock.t.Add(d)
+			s.tick()
+		}
+		s.Stop()
+	}
+	want := []string{"- waiting", "- still waiting", "- waiting", "- still waiting"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
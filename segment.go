@@ -0,0 +1,78 @@
+package spinner
+
+import (
+	"fmt"
+	"time"
+)
+
+// Segment is a part of the spinner line that animates on its own schedule.
+type Segment struct {
+	Frames   []string
+	Interval time.Duration // zero uses the spinner's interval
+	Color    string        // empty uses the spinner's color
+}
+
+type segmentState struct {
+	Segment
+	widths []int // set by configure, once the emoji width is known
+	index  int
+	due    time.Time
+}
+
+// WithSegments replaces the single animated frame with independently timed
+// segments. The first segment is drawn where the frame would be and the rest
+// follow the message and progress, before the suffix. The line is redrawn
+// whenever any segment is due to advance, and only the due segments move on
+// to their next frame. A single segment behaves like WithFrames with its own
+// interval and color. Segments without frames are left out, and reported
+// by NewWithError as ErrInvalidFrames; with none left, the spinner draws
+// its frame as usual.
+func WithSegments(segs ...Segment) Option {
+	return func(s *Spinner) {
+		s.segments = nil
+		for i, seg := range segs {
+			if len(seg.Frames) == 0 {
+				s.optErrs = append(s.optErrs, fmt.Errorf("%w: segment %d has no frames", ErrInvalidFrames, i))
+				continue
+			}
+			s.segments = append(s.segments, &segmentState{Segment: seg})
+		}
+	}
+}
+
+// segmentFrame returns the current frame of seg and its width.
+func (s *Spinner) segmentFrame(seg *segmentState) (string, int) {
+	frame := seg.Frames[seg.index]
+	if s.colorMode != ColorNever {
+		color := seg.Color
+		if color == "" {
+			color = s.color(s.frameInfo())
+		}
+		frame = sgrSpan(color) + frame + Reset
+	}
+	return frame, seg.widths[seg.index]
+}
+
+// advanceSegments moves every segment that is due at now to its next frame
+// and returns how long to wait until the next one is due. On the first call
+// after Start every segment stays on its current frame.
+func (s *Spinner) advanceSegments(now time.Time) time.Duration {
+	var next time.Time
+	for _, seg := range s.segments {
+		interval := seg.Interval
+		if interval <= 0 {
+			interval = s.interval()
+		}
+		switch {
+		case seg.due.IsZero():
+			seg.due = now.Add(interval)
+		case !now.Before(seg.due):
+			seg.index = (seg.index + 1) % len(seg.Frames)
+			seg.due = now.Add(interval)
+		}
+		if next.IsZero() || seg.due.Before(next) {
+			next = seg.due
+		}
+	}
+	return next.Sub(now)
+}
@@ -0,0 +1,108 @@
+package spinner_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestSegments(t *testing.T) {
+	var lines []string
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithSuffix("."),
+		spinner.WithSegments(
+			spinner.Segment{Frames: []string{"a0", "a1", "a2", "a3"}, Interval: 10 * time.Millisecond},
+			spinner.Segment{Frames: []string{"b0", "b1"}, Interval: 40 * time.Millisecond},
+		),
+		spinner.WithRecorder(&lines),
+	)
+	s.Start()
+	time.Sleep(250 * time.Millisecond)
+	s.Stop()
+
+	if len(lines) < 5 {
+		t.Fatalf("recorded %d lines, want at least 5", len(lines))
+	}
+	if lines[0] != "a0 b0 ." {
+		t.Errorf("first line = %q, want %q", lines[0], "a0 b0 .")
+	}
+	var changesA, changesB int
+	for i := 1; i < len(lines); i++ {
+		prev, cur := strings.Fields(lines[i-1]), strings.Fields(lines[i])
+		if len(cur) != 3 {
+			t.Fatalf("line %q does not have two segments and a suffix", lines[i])
+		}
+		if prev[0] != cur[0] {
+			changesA++
+		}
+		if prev[1] != cur[1] {
+			changesB++
+		}
+		if prev[0] == cur[0] && prev[1] == cur[1] {
+			t.Errorf("line %d redrawn with no segment due: %q", i, lines[i])
+		}
+	}
+	if changesB == 0 || changesA < 2*changesB {
+		t.Errorf("fast segment advanced %d times, slow segment %d times", changesA, changesB)
+	}
+}
+
+func TestSingleSegment(t *testing.T) {
+	var lines []string
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorAlways),
+		spinner.WithSegments(spinner.Segment{Frames: spinner.Line, Interval: 5 * time.Millisecond, Color: spinner.Blue}),
+		spinner.WithRecorder(&lines),
+	)
+	s.Start()
+	time.Sleep(100 * time.Millisecond)
+	s.Stop()
+	if len(lines) < len(spinner.Line) {
+		t.Fatalf("recorded %d lines, want at least %d", len(lines), len(spinner.Line))
+	}
+	for i, got := range lines {
+		if want := spinner.Blue + spinner.Line[i%len(spinner.Line)] + spinner.Reset; got != want {
+			t.Errorf("line %d = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestEmptySegments(t *testing.T) {
+	for _, segs := range [][]spinner.Segment{
+		nil,
+		{{Interval: time.Millisecond}},
+		{{Frames: []string{"a"}}, {Color: spinner.Red}},
+	} {
+		var lines []string
+		s := spinner.New(
+			spinner.WithWriter(&bytes.Buffer{}),
+			spinner.WithColorMode(spinner.ColorNever),
+			spinner.WithFrames([]string{"x"}),
+			spinner.WithInterval(time.Millisecond),
+			spinner.WithSegments(segs...),
+			spinner.WithRecorder(&lines),
+		)
+		s.Start()
+		time.Sleep(20 * time.Millisecond)
+		s.Stop()
+		want := "x"
+		if len(segs) == 2 {
+			want = "a"
+		}
+		if len(lines) == 0 || lines[0] != want {
+			t.Errorf("WithSegments(%+v) drew %q, want %q first", segs, lines, want)
+		}
+
+		_, err := spinner.NewWithError(spinner.WithSegments(segs...))
+		if got, want := errors.Is(err, spinner.ErrInvalidFrames), len(segs) > 0; got != want {
+			t.Errorf("NewWithError(WithSegments(%+v)) = %v", segs, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package spinner
+
+// sequence is the frame sequence a running spinner is playing.
+type sequence int
+
+const (
+	playLoop  sequence = iota // the frames, over and over
+	playIntro                 // WithIntroFrames, once, before the loop
+	playOutro                 // WithOutroFrames, once, on Stop
+	playHold                  // the outro has finished; its last frame stays
+)
+
+// WithIntroFrames sets frames that are played once when the spinner starts,
+// before the animation settles into its looping frames, such as a short
+// spin-up. It has no effect with WithSegments or on a dumb terminal.
+func WithIntroFrames(frames []string) Option {
+	return func(s *Spinner) {
+		s.introFrames = frames
+	}
+}
+
+// WithOutroFrames sets frames that Stop, Success, Fail, Warn and Stopf play
+// to completion, at the current interval, before they finish the line. With
+// WithClearOnStop(false) the last outro frame is the one left on screen.
+// StopNow skips the outro, and so does stopping a spinner that is paused by
+// WithPauseOnBlur. It has no effect with WithSegments or on a dumb terminal.
+func WithOutroFrames(frames []string) Option {
+	return func(s *Spinner) {
+		s.outroFrames = frames
+	}
+}
+
+// StopNow is like Stop but does not wait for the WithOutroFrames outro.
+func (s *Spinner) StopNow() {
+	s.end("stopped", func() string { return "" }, false, false)
+}
+
+// play switches to frames for seq, keeping the looping frames aside to be
+// restored by playLoopFrames. The caller must hold mu.
+func (s *Spinner) play(seq sequence, frames []string) {
+	if s.playing == playLoop {
+		s.loopFrames = s.frames
+	}
+	s.setFrames(frames)
+	s.index, s.playing = 0, seq
+}
+
+// playLoopFrames goes back to the looping frames if an intro or outro is
+// playing. The caller must hold mu.
+func (s *Spinner) playLoopFrames() {
+	if s.playing == playLoop {
+		return
+	}
+	s.setFrames(s.loopFrames)
+	s.index, s.playing, s.loopFrames = 0, playLoop, nil
+}
+
+// replaceFrames sets the looping frames, leaving an intro or outro that is
+// playing to finish first. The caller must hold mu.
+func (s *Spinner) replaceFrames(frames []string) {
+	if s.playing == playLoop {
+		s.setFrames(frames)
+		s.index = 0
+		return
+	}
+	s.loopFrames = frames
+}
+
+// sequenceEnded is called by tick when the frames it is playing wrap
+// around, and moves on from an intro or outro. The caller must hold mu.
+func (s *Spinner) sequenceEnded() {
+	switch s.playing {
+	case playIntro:
+		s.playLoopFrames()
+	case playOutro:
+		s.index, s.playing = len(s.frames)-1, playHold
+		close(s.outroDone)
+	case playHold:
+		s.index = len(s.frames) - 1
+	}
+}
+
+// playOutro plays the outro and waits for it to finish, or for the render
+// goroutine to exit on a write error. The caller must hold lifecycle.
+func (s *Spinner) playOutro() {
+	s.mu.Lock()
+	if len(s.outroFrames) == 0 || s.state != stateRunning || s.plain || s.segments != nil || s.suspended || s.blurred {
+		s.mu.Unlock()
+		return
+	}
+	s.play(playOutro, s.outroFrames)
+	s.outroDone = make(chan struct{})
+	done, exited := s.outroDone, s.exited
+	s.mu.Unlock()
+	select {
+	case <-done:
+	case <-exited:
+	}
+}
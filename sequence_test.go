@@ -0,0 +1,135 @@
+package spinner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIntroOutro(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	clock := &fakeClock{time.Unix(0, 0)}
+	var lines []string
+	s := New(
+		WithWriter(&countingWriter{}),
+		WithColorMode(ColorNever),
+		WithClearOnStop(false),
+		WithFrames([]string{"a", "b"}),
+		WithIntroFrames([]string{"i1", "i2"}),
+		WithOutroFrames([]string{"o1", "o2", "o3"}),
+		WithRecorder(&lines),
+	)
+	s.now, s.loop = clock.now, manualLoop
+	tick := func() {
+		clock.t = clock.t.Add(60 * time.Millisecond)
+		s.tick()
+	}
+
+	s.Start()
+	for i := 0; i < 5; i++ {
+		tick()
+	}
+	if want := []string{"i1", "i2", "a", "b", "a"}; !reflect.DeepEqual(lines, want) {
+		t.Fatalf("intro and loop drew %q, want %q", lines, want)
+	}
+
+	lines = nil
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+	for !s.playingOutro() {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 3; i++ {
+		tick()
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the outro")
+	}
+	if want := []string{"o1", "o2", "o3"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("outro drew %q, want %q", lines, want)
+	}
+	if got := s.Snapshot().Frame; got != "o3" {
+		t.Errorf("frame left on screen = %q, want the last outro frame", got)
+	}
+
+	// The next run plays the intro again and StopNow skips the outro.
+	lines = nil
+	s.Start()
+	tick()
+	s.StopNow()
+	if want := []string{"i1"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("second run drew %q, want %q", lines, want)
+	}
+	if c := s.Config(); c.Frames != 2 {
+		t.Errorf("after stopping, Config().Frames = %d, want the 2 looping frames", c.Frames)
+	}
+}
+
+func TestOutroRealTime(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var lines []string
+	s := New(
+		WithWriter(&countingWriter{}),
+		WithColorMode(ColorNever),
+		WithInterval(time.Millisecond),
+		WithFrames([]string{"a"}),
+		WithOutroFrames([]string{"o1", "o2"}),
+		WithRecorder(&lines),
+	)
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.Success("done")
+	// The last outro frame may be drawn again while Success takes over.
+	i := len(lines) - 1
+	for i > 0 && lines[i] == "o2" && lines[i-1] == "o2" {
+		i--
+	}
+	if i < 1 || lines[i-1] != "o1" || lines[i] != "o2" {
+		t.Errorf("lines = %q, want them to end with the outro", lines)
+	}
+}
+
+// playingOutro reports whether Stop has started the outro.
+func (s *Spinner) playingOutro() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.playing == playOutro
+}
+
+func TestEmptyIntroOutro(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	clock := &fakeClock{time.Unix(0, 0)}
+	var lines []string
+	s := New(
+		WithWriter(&countingWriter{}),
+		WithColorMode(ColorNever),
+		WithFrames([]string{"a", "b"}),
+		WithIntroFrames([]string{}),
+		WithOutroFrames([]string{}),
+		WithRecorder(&lines),
+	)
+	s.now, s.loop = clock.now, manualLoop
+	s.Start()
+	for i := 0; i < 3; i++ {
+		clock.t = clock.t.Add(60 * time.Millisecond)
+		s.tick()
+	}
+	if want := []string{"a", "b", "a"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("empty intro drew %q, want %q", lines, want)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop with an empty outro did not return")
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !unix
+
+package spinner
+
+func (s *Spinner) handleSignals(done <-chan struct{}) {}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package spinner
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// handleSignals handles job control signals until done is closed.
+func (s *Spinner) handleSignals(done <-chan struct{}) {
+	tstp := make(chan os.Signal, 1)
+	cont := make(chan os.Signal, 1)
+	signal.Notify(tstp, syscall.SIGTSTP)
+	signal.Notify(cont, syscall.SIGCONT)
+	defer signal.Stop(tstp)
+	defer signal.Stop(cont)
+	for {
+		select {
+		case <-done:
+			return
+		case <-tstp:
+			s.suspend()
+			// Stop relaying SIGTSTP so that resending it stops the process
+			// group with the default action.
+			signal.Stop(tstp)
+			syscall.Kill(0, syscall.SIGTSTP)
+		case <-cont:
+			s.resume()
+			signal.Notify(tstp, syscall.SIGTSTP)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+//go:build unix
+
+package spinner
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSuspendResume(t *testing.T) {
+	var buf syncBuffer
+	s := New(WithWriter(&buf), WithForceTTY(true), WithFrames([]string{"ab"}), WithInterval(5*time.Millisecond))
+	s.Start()
+	defer s.Stop()
+	time.Sleep(20 * time.Millisecond)
+
+	s.suspend()
+	out := buf.String()
+	if want := "\r  \r" + ShowCursor; !strings.HasSuffix(out, want) {
+		t.Fatalf("suspend output %q does not end with %q", out, want)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := buf.String(); got != out {
+		t.Fatalf("spinner drew while suspended: %q", got[len(out):])
+	}
+
+	s.resume()
+	time.Sleep(20 * time.Millisecond)
+	if got := buf.String()[len(out):]; !strings.HasPrefix(got, HideCursor+"\r") {
+		t.Errorf("resume output %q does not hide the cursor and repaint", got)
+	}
+}
+
+func TestSIGCONT(t *testing.T) {
+	var buf syncBuffer
+	s := New(WithWriter(&buf), WithForceTTY(true), WithSignalHandling(true), WithInterval(5*time.Millisecond))
+	s.Start()
+	defer s.Stop()
+	time.Sleep(20 * time.Millisecond)
+	s.suspend()
+	n := len(buf.String())
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGCONT)
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String()[n:], HideCursor) {
+		if time.Now().After(deadline) {
+			t.Fatal("spinner did not resume after SIGCONT")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
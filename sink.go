@@ -0,0 +1,113 @@
+package spinner
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Sink receives what a spinner would draw, as structured data instead of
+// bytes, for programs that render through a library such as tcell and set
+// colors as attributes rather than with escape sequences.
+type Sink interface {
+	// Render is called on every tick with the frame to show, and once
+	// more with Stopped set when the spinner stops. It is called with the
+	// spinner's lock held, so it must not call the spinner's methods.
+	Render(f Frame)
+}
+
+// Frame is what a spinner shows on one tick, for a Sink.
+type Frame struct {
+	FrameInfo
+	Text    string // the frame, empty for a spinner without frames
+	Color   Color  // the frame's color
+	Prefix  string
+	Message string
+	Suffix  string
+	Line    string // the whole line, as the spinner would draw it without color
+	// Stopped is set on the last Frame of a run. Message then holds the
+	// line the spinner leaves behind, such as Success's check mark and
+	// message, and is empty if it leaves none.
+	Stopped bool
+}
+
+// WithSink sends every frame to sink instead of drawing it on the writer.
+// The spinner keeps its schedule, messages and progress as usual, but
+// writes nothing itself apart from Println and Printf output: no frames,
+// colors or escape sequences. Without a sink the spinner draws on its
+// writer.
+func WithSink(sink Sink) Option {
+	return func(s *Spinner) {
+		s.sink = sink
+	}
+}
+
+// sinkFrame returns the Frame for the current tick.
+func (s *Spinner) sinkFrame(line string) Frame {
+	info := s.frameInfo()
+	f := Frame{
+		FrameInfo: info,
+		Color:     ParseColor(s.color(info)),
+		Prefix:    s.prefix,
+		Message:   s.message,
+		Suffix:    s.suffix,
+		Line:      line,
+	}
+	switch {
+	case s.frameFunc != nil && s.playing == playLoop:
+		// The frame func was called by render for line; calling it again
+		// could give another frame, and would cost a second call.
+		f.Text = s.funcFrame
+	case len(s.frames) > 0:
+		f.Text = s.frames[s.index]
+	}
+	return f
+}
+
+// Color is a foreground color taken apart from its escape sequence.
+type Color struct {
+	SGR     string // the escape sequence it was parsed from
+	Indexed bool   // Index holds a 256-color palette index
+	Index   uint8
+	RGB     bool // R, G and B hold a 24-bit color
+	R, G, B uint8
+}
+
+// ParseColor parses the foreground color set by a Select Graphic Rendition
+// sequence, such as those returned by Color256 or SGR. It understands the
+// 16 basic colors, which map to palette indexes 0 to 15, 256-color indexes
+// and 24-bit colors, and ignores any other attributes in the sequence. A
+// sequence it cannot parse gives a Color with neither Indexed nor RGB set.
+func ParseColor(sgr string) Color {
+	c := Color{SGR: sgr}
+	body, ok := strings.CutPrefix(sgr, "\033[")
+	if !ok {
+		return c
+	}
+	if body, ok = strings.CutSuffix(body, "m"); !ok {
+		return c
+	}
+	var codes []int
+	for _, f := range strings.Split(body, ";") {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || n > 255 {
+			return c
+		}
+		codes = append(codes, n)
+	}
+	for i := 0; i < len(codes); i++ {
+		switch n := codes[i]; {
+		case n >= 30 && n <= 37:
+			c.Indexed, c.RGB, c.Index = true, false, uint8(n-30)
+		case n >= 90 && n <= 97:
+			c.Indexed, c.RGB, c.Index = true, false, uint8(n-90+8)
+		case n == 38 && i+2 < len(codes) && codes[i+1] == 5:
+			c.Indexed, c.RGB, c.Index = true, false, uint8(codes[i+2])
+			i += 2
+		case n == 38 && i+4 < len(codes) && codes[i+1] == 2:
+			c.Indexed, c.RGB = false, true
+			c.R, c.G, c.B = uint8(codes[i+2]), uint8(codes[i+3]), uint8(codes[i+4])
+			i += 4
+		}
+	}
+	return c
+}
@@ -0,0 +1,104 @@
+package spinner_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+type recordingSink struct{ frames []spinner.Frame }
+
+func (r *recordingSink) Render(f spinner.Frame) { r.frames = append(r.frames, f) }
+
+func TestSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &recordingSink{}
+	s := spinner.New(
+		spinner.WithWriter(&buf),
+		spinner.WithSink(sink),
+		spinner.WithColorMode(spinner.ColorAlways),
+		spinner.WithColor(spinner.Color256(208)),
+		spinner.WithFrames([]string{"-", "+"}),
+		spinner.WithMessage("loading"),
+		spinner.WithInterval(time.Millisecond),
+	)
+	s.SetProgress(1, 4)
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	s.Println("above")
+	s.Success("loaded")
+
+	if got := buf.String(); got != "above\n" {
+		t.Errorf("spinner wrote %q, want only the Println output", got)
+	}
+	if len(sink.frames) < 3 {
+		t.Fatalf("sink got %d frames, want at least 3", len(sink.frames))
+	}
+	for i, f := range sink.frames[:2] {
+		want := []string{"-", "+"}[i]
+		if f.Text != want || f.Message != "loading" || f.Line != want+" loading 25%" || f.Current != 1 || f.Total != 4 || f.Stopped {
+			t.Errorf("frame %d = %+v", i, f)
+		}
+		if !f.Color.Indexed || f.Color.Index != 208 {
+			t.Errorf("frame %d color = %+v, want index 208", i, f.Color)
+		}
+	}
+	last := sink.frames[len(sink.frames)-1]
+	if !last.Stopped || last.Message != "✔ loaded" {
+		t.Errorf("last frame = %+v, want stopped with the final line", last)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		sgr  string
+		want spinner.Color
+	}{
+		{spinner.Red, spinner.Color{Indexed: true, Index: 9}},
+		{"\033[31m", spinner.Color{Indexed: true, Index: 1}},
+		{"\033[1;94m", spinner.Color{Indexed: true, Index: 12}},
+		{spinner.SGR(1, 38, 2, 255, 128, 0), spinner.Color{RGB: true, R: 255, G: 128}},
+		{"", spinner.Color{}},
+		{"red", spinner.Color{}},
+		{"\033[38;5;300m", spinner.Color{}},
+	}
+	for _, tt := range tests {
+		tt.want.SGR = tt.sgr
+		if got := spinner.ParseColor(tt.sgr); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseColor(%q) = %+v, want %+v", tt.sgr, got, tt.want)
+		}
+	}
+}
+
+func TestSinkFrameFunc(t *testing.T) {
+	sink := &recordingSink{}
+	calls := 0
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithSink(sink),
+		spinner.WithFrameFunc(func() string {
+			calls++
+			return strings.Repeat("*", calls)
+		}),
+		spinner.WithInterval(time.Millisecond),
+	)
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+
+	if len(sink.frames) < 2 {
+		t.Fatalf("sink got %d frames, want at least 2", len(sink.frames))
+	}
+	for i, f := range sink.frames {
+		if want := strings.Repeat("*", min(i+1, calls)); f.Text != want || !f.Stopped && f.Line != want {
+			t.Errorf("frame %d = %+v, want text %q", i, f, want)
+		}
+	}
+	if calls != len(sink.frames)-1 {
+		t.Errorf("frame func called %d times for %d ticks", calls, len(sink.frames)-1)
+	}
+}
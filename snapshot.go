@@ -0,0 +1,70 @@
+package spinner
+
+import "time"
+
+// Snapshot describes what a spinner was showing, as of the last frame it
+// drew or the last time it started or stopped.
+type Snapshot struct {
+	Active    bool
+	Line      string // the whole line, as drawn
+	Frame     string
+	Index     int
+	Message   string
+	Elapsed   time.Duration // time since Start
+	Current   int64
+	Total     int64
+	Err       error // the write error that stopped the spinner, if any
+	ColorMode ColorMode
+}
+
+// Snapshot returns the spinner's latest Snapshot. It never blocks, so it is
+// safe to call from a panic handler or a signal handler while the spinner
+// is in the middle of drawing.
+func (s *Spinner) Snapshot() Snapshot {
+	return *s.snapshot.Load()
+}
+
+// WithOnStop sets a function called with the final Snapshot each time the
+// spinner stops, whether by Stop, Success, Fail or a failed write. It is
+// called after the terminal has been restored, without any of the spinner's
+// locks held.
+func WithOnStop(f func(Snapshot)) Option {
+	return func(s *Spinner) {
+		s.onStop = f
+	}
+}
+
+// publish stores a new Snapshot for Snapshot to return.
+func (s *Spinner) publish() {
+	snap := &Snapshot{
+		Active:    s.state == stateRunning,
+		Line:      s.lastLine,
+		Index:     s.index,
+		Message:   s.message,
+		Current:   s.current,
+		Total:     s.total,
+		Err:       s.lastErr,
+		ColorMode: s.colorMode,
+	}
+	if s.index < len(s.frames) {
+		snap.Frame = s.frames[s.index]
+	}
+	if !s.startedAt.IsZero() {
+		snap.Elapsed = s.now().Sub(s.startedAt)
+	}
+	s.snapshot.Store(snap)
+}
+
+// publishStopped records that the spinner has stopped and marks the latest
+// Snapshot inactive, keeping the frame and line that were last drawn.
+func (s *Spinner) publishStopped() {
+	s.stops++
+	s.stoppedAt = s.now()
+	snap := *s.snapshot.Load()
+	snap.Active = false
+	snap.Err = s.lastErr
+	if !s.startedAt.IsZero() {
+		snap.Elapsed = s.stoppedAt.Sub(s.startedAt)
+	}
+	s.snapshot.Store(&snap)
+}
@@ -0,0 +1,83 @@
+package spinner_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestSnapshot(t *testing.T) {
+	var s *spinner.Spinner
+	var during spinner.Snapshot
+	stopped := make(chan spinner.Snapshot, 1)
+	s = spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithFrames([]string{"a", "b"}),
+		spinner.WithMessage("building"),
+		spinner.WithInterval(time.Millisecond),
+		// Progress formatting runs while the spinner holds its lock.
+		spinner.WithProgressFormat(func(float64, time.Duration) string {
+			during = s.Snapshot()
+			return "50%"
+		}),
+		spinner.WithOnStop(func(snap spinner.Snapshot) { stopped <- snap }),
+	)
+	if snap := s.Snapshot(); snap.Active || snap.ColorMode != spinner.ColorNever {
+		t.Errorf("Snapshot before Start = %+v", snap)
+	}
+	s.SetProgress(2, 4)
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+
+	snap := s.Snapshot()
+	if !snap.Active || snap.Message != "building" || snap.Current != 2 || snap.Total != 4 || snap.Elapsed <= 0 {
+		t.Errorf("Snapshot while running = %+v", snap)
+	}
+	if want := snap.Frame + " building 50%"; snap.Line != want {
+		t.Errorf("Snapshot.Line = %q, want %q", snap.Line, want)
+	}
+	s.Stop()
+	if !during.Active {
+		t.Errorf("Snapshot taken while drawing = %+v", during)
+	}
+
+	select {
+	case final := <-stopped:
+		if final.Active || !strings.HasSuffix(final.Line, "building 50%") || final.Err != nil {
+			t.Errorf("OnStop got %+v", final)
+		}
+	default:
+		t.Fatal("OnStop not called by Stop")
+	}
+	s.Stop()
+	select {
+	case <-stopped:
+		t.Error("OnStop called by Stop on a stopped spinner")
+	default:
+	}
+}
+
+func TestSnapshotWriteError(t *testing.T) {
+	stopped := make(chan spinner.Snapshot, 1)
+	s := spinner.New(
+		spinner.WithWriter(&failAfter{n: 2}),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithOnStop(func(snap spinner.Snapshot) { stopped <- snap }),
+	)
+	s.Start()
+	select {
+	case snap := <-stopped:
+		if snap.Active || snap.Err == nil {
+			t.Errorf("OnStop after write error got %+v", snap)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnStop not called after write error")
+	}
+	if s.Snapshot().Err == nil {
+		t.Error("Snapshot does not report the write error")
+	}
+}
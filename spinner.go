@@ -1,27 +1,231 @@
 package spinner
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Spinner struct {
+	lifecycle  sync.Mutex // serializes Start and Stop
 	mu         sync.Mutex
 	frames     []string
+	rawFrames  bool // WithRawFrames: frames carry their own styling
+	frameFunc  func(FrameInfo) string
+	funcFrame  string           // the frame frameFunc last returned to render
+	widthFunc  func(string) int // WithWidthFunc
+	widths     []int
+	emojiWidth int // columns for a narrow character followed by U+FE0F
+	lastWidth  int
+	lastLine   string
+	cache      *prerendered // lines of a static spinner, reset by setFrames
+	lastHeight int          // lines the last draw covered, when frames span several
 	index      int
-	active     bool
-	stop       chan struct{}
+	rewind     bool // the next Start begins at the first frame
+	loops      int
+	ticks      int
+	startedAt  time.Time
+	stoppedAt  time.Time     // when the last run ended
+	starts     int           // Start calls that started the spinner
+	stops      int           // runs that have ended, by Stop or by a write error
+	totalTicks int           // ticks across every run
+	target     time.Time     // when the next frame is due, on an ideal schedule
+	drift      time.Duration // how late the last frame was against target
+	state      state
+	active     atomic.Bool   // state == stateRunning, for readers that must not take mu
+	stop       chan struct{} // closed by Stop to end the render goroutine
+	exited     chan struct{} // closed when the render goroutine returns
+	done       chan struct{}
 	writer     io.Writer
 	interval   func() time.Duration
-	color      func() string
-	hideCursor bool
+	color      func(FrameInfo) string
+
+	// The caller's color and interval funcs, when color and interval
+	// return the values evalFuncs last got from them.
+	userColor     func(FrameInfo) string
+	userInterval  func() time.Duration
+	colorValue    string
+	intervalValue time.Duration
+	evaluating    bool // a call to them is under way
+	slowFuncs     int  // calls that took longer than an interval
+	evalSeq       int  // number of the latest evalRequest
+	evalRun       int  // number of the run whose results are kept
+	evalReqs      chan evalRequest
+	evalDone      chan int // the number of each request evaluated
+	evalTimer     *time.Timer
+
+	colorMode  ColorMode
+	background Background
+	detectBG   bool // BackgroundAuto: detect the background in Start
+	cursorMode CursorMode
+	cursorHeld bool // this spinner holds an AcquireCursorHide on writer
+	term       *Term
+
+	clearOnStop    bool
+	stopSymbol     string // printed by Stopf
+	finalFrame     string // WithFinalFrame
+	successColor   string
+	failColor      string
+	warnColor      string
+	successSymbol  string
+	failSymbol     string
+	warnSymbol     string
+	messageColor   string
+	stopNewline    bool
+	skipDuplicates bool
+	minRender      time.Duration // WithMinRenderInterval
+	lastDraw       time.Time     // when the last frame was written
+	adaptMin       time.Duration // WithAdaptiveInterval
+	adaptMax       time.Duration
+	latency        time.Duration // smoothed time a frame write takes
+	lastInterval   time.Duration // wait before the latest frame's successor
+
+	driftCompensation bool
+	strictFrames      bool // WithStrictFrames
+
+	fixedInterval  time.Duration
+	intervalSource Source
+	intervalOpt    string
+	colorSource    Source
+	colorOpt       string
+	optErrs        []error
+	envErrs        []error // invalid WithEnvOverrides variables
+	envOverrides   bool
+	disabled       bool // SPINNER_DISABLED: Start does nothing
+	noop           bool // NewNoop; kept across Reset
+	autoStart      bool // WithAutoStart: New and Reset start the spinner
+
+	message         string
+	pendingMessage  atomic.Pointer[string]   // set by UpdateMessage, applied by the next tick
+	pendingProgress atomic.Pointer[[2]int64] // current and total set by WatchProgress, applied likewise
+	countdown       *countdown               // the Countdown shown in place of the message
+	current         int64
+	total           int64
+	barWidth        int
+	progressFormat  func(fraction float64, elapsed time.Duration) string
+	percentFormat   func(current, total int64) string
+	durationFormat  func(time.Duration) string
+	determinate     bool // SetProgress has reported a total; draw the bar
+
+	rawMessages bool
+	newline     string // replaces line breaks in messages
+
+	maxLine    int
+	truncation Truncation
+
+	row, col int // WithScreenPosition; row 0 draws inline
+	columns  int // width of the terminal, or 0 if unknown
+
+	activityLines   int
+	activity        []string // the latest Activity lines, oldest first
+	persistActivity bool
+
+	prefix    string
+	suffix    string
+	separator string
+
+	segments []*segmentState
+
+	tty         bool
+	forceTTY    bool
+	syncMode    SyncMode
+	syncUpdates bool // repaints are wrapped in synchronized output
+	inSync      bool // between beginSync and endSync
+	oscProgress bool
+	oscPercent  int64 // last percentage sent with OSC 9;4, or -1
+
+	titleFormat  string
+	titleRestore string
+	title        string    // last title sent with OSC 2
+	titleAt      time.Time // when title was sent
+	titleSaved   bool      // the terminal's title is on its title stack
+
+	plain          bool // TERM=dumb: print once instead of animating
+	forceAnimation bool
+	testMode       atomic.Bool // WithTestMode: plain, with a line per step
+	quiet          bool        // WithQuietUntilError: plain, printing only Fail's line
+
+	signalHandling bool
+	suspended      bool
+
+	pauseOnBlur    bool
+	focusInput     bool // FocusReader has been called
+	focusReporting bool // the terminal has been asked to report focus
+	blurred        bool
+
+	writerLock *WriterLock // held around each group of writes
+	sharedLock bool        // writerLock is shared with spinners on the same writer
+	lockHeld   bool        // holdWriterLock has registered writerLock
+
+	recorder *[]string
+	errs     chan error
+	lastErr  error
+
+	jsonOut   io.Writer // WithJSONOutput: write Events instead of drawing
+	jsonEvery time.Duration
+	sink      Sink // WithSink: hand frames to sink instead of drawing
+
+	snapshot atomic.Pointer[Snapshot]
+	onStop   func(Snapshot)
+
+	safeWriters []*safeWriter // from SafeWriter, kept across Reset
+
+	elapsed bool
+
+	phases []Phase
+	phase  int // index of the phase shown, or -1
+
+	schedule          []MessageAt
+	scheduled         int    // index of the scheduled message shown, or -1
+	baseMessage       string // the message the schedule replaced
+	overridden        bool   // UpdateMessage was called during this run
+	overridePermanent bool
+
+	introFrames []string
+	outroFrames []string
+	playing     sequence
+	loopFrames  []string      // the looping frames, while an intro or outro plays
+	outroDone   chan struct{} // closed when the outro has played
+
+	// now and loop are the spinner's clock and render loop. Tests replace
+	// them to drive ticks by hand against a fake clock.
+	now  func() time.Time
+	loop func(stop <-chan struct{}, exited chan<- struct{})
 }
 
 type Option func(*Spinner)
 
+// state is the lifecycle state of a Spinner. Start moves an idle spinner to
+// running, and Stop moves it through stopping, while the render goroutine
+// exits, back to idle. A spinner whose writer fails goes straight from
+// running to idle.
+type state int
+
+const (
+	stateIdle state = iota
+	stateRunning
+	stateStopping
+)
+
+// WithOptions bundles opts into a single Option, so that a set of options,
+// such as a house style, can be shared and passed along with others. The
+// bundled options are applied in order at the point where WithOptions
+// appears, so options after it override them and options before it are
+// overridden by them, exactly as if opts had been spliced in.
+func WithOptions(opts ...Option) Option {
+	return func(s *Spinner) {
+		for _, opt := range opts {
+			opt(s)
+		}
+	}
+}
+
 func WithWriter(w io.Writer) Option {
 	return func(s *Spinner) {
 		s.writer = w
@@ -30,112 +234,1258 @@ func WithWriter(w io.Writer) Option {
 
 func WithInterval(d time.Duration) Option {
 	return func(s *Spinner) {
+		s.setSource(&s.intervalSource, &s.intervalOpt, SourceFixed, "WithInterval")
+		s.fixedInterval = d
 		s.interval = func() time.Duration {
 			return d
 		}
 	}
 }
 
+// WithFrames sets the frames of the animation. Frames may span several
+// lines; they are padded to the height and width of the largest, and the
+// message follows the last line. With no frames at all the spinner draws
+// no glyph, only its prefix, message, progress and suffix, which suits
+// tasks that show a progress bar or percentage and nothing else.
 func WithFrames(frames []string) Option {
 	return func(s *Spinner) {
 		s.frames = frames
 	}
 }
 
+// WithFrameFunc draws the frame returned by f on every tick in place of the
+// frames set by WithFrames, for frames computed on the fly rather than
+// picked from a fixed set. The frame is colored like any other, unless
+// WithRawFrames is set, and its width is measured each time, so a shorter
+// frame clears what a longer one left behind.
+func WithFrameFunc(f func() string) Option {
+	return func(s *Spinner) {
+		s.frameFunc = func(FrameInfo) string { return f() }
+	}
+}
+
+// WithFrameInfoFunc is like WithFrameFunc but f is told about the frame
+// being drawn, including the spinner's progress, as ProgressFrameFunc needs.
+func WithFrameInfoFunc(f func(FrameInfo) string) Option {
+	return func(s *Spinner) {
+		s.frameFunc = f
+	}
+}
+
+// WithRawFrames writes frames verbatim instead of wrapping each one in the
+// spinner's color and Reset, so that frames can carry their own escape
+// sequences, such as a different color per glyph. The trade-off is that the
+// spinner no longer controls the frames' appearance: WithColor and color
+// functions do not apply to them, and their escape sequences are written
+// even under ColorNever or NO_COLOR. A frame should reset any styling it
+// sets, or it carries over into the message. Escape sequences are left out
+// when measuring a frame's width.
+func WithRawFrames(raw bool) Option {
+	return func(s *Spinner) {
+		s.rawFrames = raw
+	}
+}
+
+// setFrames replaces the spinner's frames and their widths.
+func (s *Spinner) setFrames(frames []string) {
+	s.frames = normalizeFrames(frames, s.width)
+	s.cache = nil
+	if !s.rawFrames {
+		s.widths = s.frameWidths(s.frames)
+		return
+	}
+	s.widths = make([]int, len(s.frames))
+	for i, f := range s.frames {
+		s.widths[i] = s.width(f[strings.LastIndexByte(f, '\n')+1:])
+	}
+}
+
+// WithIntervalFunc sets a func that returns the time to wait after each
+// frame. It is called without the spinner's lock held; a call that takes
+// longer than an interval is waited out with the previous interval.
 func WithIntervalFunc(f func() time.Duration) func(*Spinner) {
 	return func(s *Spinner) {
+		s.setSource(&s.intervalSource, &s.intervalOpt, SourceFunc, "WithIntervalFunc")
+		s.fixedInterval = 0
 		s.interval = f
 	}
 }
 
 func WithColor(color string) func(*Spinner) {
 	return func(s *Spinner) {
-		s.color = func() string { return color }
+		s.setSource(&s.colorSource, &s.colorOpt, SourceFixed, "WithColor")
+		s.color = func(FrameInfo) string { return color }
 	}
 }
 
+// WithColorFunc sets a func that returns the color of each frame. It is
+// called without the spinner's lock held; a frame whose call takes longer
+// than an interval is drawn in the previous color.
 func WithColorFunc(f func() string) func(*Spinner) {
 	return func(s *Spinner) {
+		s.setSource(&s.colorSource, &s.colorOpt, SourceFunc, "WithColorFunc")
+		s.color = func(FrameInfo) string { return f() }
+	}
+}
+
+// FrameInfo describes the frame being drawn.
+type FrameInfo struct {
+	Index           int           // index of the frame in the frame set
+	Frames          int           // number of frames in the set
+	Loop            int           // completed passes through the frame set
+	Elapsed         time.Duration // time since Start
+	TicksSinceStart int           // frames drawn since Start before this one
+	Current, Total  int64         // progress from SetProgress
+}
+
+// WithColorFrameFunc sets a color func that is told which frame is being
+// drawn, so effects can follow the animation instead of the wall clock. It
+// is called as the func of WithColorFunc is.
+func WithColorFrameFunc(f func(FrameInfo) string) Option {
+	return func(s *Spinner) {
+		s.setSource(&s.colorSource, &s.colorOpt, SourceFunc, "WithColorFrameFunc")
 		s.color = f
 	}
 }
 
+// WithHideCursor sets the cursor mode to CursorHide, the default, or to
+// CursorNone.
 func WithHideCursor(hide bool) func(*Spinner) {
 	return func(s *Spinner) {
-		s.hideCursor = hide
+		s.cursorMode = CursorNone
+		if hide {
+			s.cursorMode = CursorHide
+		}
+	}
+}
+
+// WithClearOnStop controls whether Stop erases the spinner line. When false
+// the last frame is left on screen.
+func WithClearOnStop(clear bool) Option {
+	return func(s *Spinner) {
+		s.clearOnStop = clear
+	}
+}
+
+// WithFinalNewline makes Stop move the cursor to the start of the next line
+// once the spinner line has been cleared or left in place. Success, Fail,
+// Warn and Stopf always end their line with a newline.
+func WithFinalNewline(newline bool) Option {
+	return func(s *Spinner) {
+		s.stopNewline = newline
+	}
+}
+
+// WithStopNewline is the same as WithFinalNewline.
+func WithStopNewline(newline bool) Option {
+	return WithFinalNewline(newline)
+}
+
+// WithMessage sets the message shown after the frame.
+func WithMessage(msg string) Option {
+	return func(s *Spinner) {
+		s.message = msg
+	}
+}
+
+// UpdateMessage replaces the message shown after the frame. It only stores
+// the message without taking the spinner's lock, and the next frame drawn
+// shows it, so it is cheap to call far more often than the spinner redraws.
+// The most recent message wins. The exception is WithTestMode, in which
+// UpdateMessage takes the lock and writes the step's line before it
+// returns.
+func (s *Spinner) UpdateMessage(msg string) {
+	s.pendingMessage.Store(&msg)
+	if s.testMode.Load() {
+		s.testStep()
+	}
+}
+
+// applyMessage takes over a message stored by UpdateMessage, and progress
+// stored by WatchProgress.
+func (s *Spinner) applyMessage() {
+	if p := s.pendingProgress.Swap(nil); p != nil {
+		s.setProgress(p[0], p[1])
+	}
+	if msg := s.pendingMessage.Swap(nil); msg != nil {
+		s.message = s.sanitize(*msg)
+		s.overridden = true
+	}
+}
+
+// WithPrefix sets text shown before the frame.
+func WithPrefix(prefix string) Option {
+	return func(s *Spinner) {
+		s.prefix = prefix
+	}
+}
+
+// WithSuffix sets text shown at the end of the line.
+func WithSuffix(suffix string) Option {
+	return func(s *Spinner) {
+		s.suffix = suffix
+	}
+}
+
+// WithSeparator sets the text placed between the prefix, frame, message,
+// progress and suffix, and between a finisher's symbol and its message.
+// It defaults to a single space.
+func WithSeparator(sep string) Option {
+	return func(s *Spinner) {
+		s.separator = sep
+	}
+}
+
+// WithQuietUntilError keeps the spinner silent unless it fails: it draws
+// nothing while running, and Success, Warn, Stop and Stopf print nothing
+// either. Fail prints its line as usual, with the latest message if it is
+// given none, so that a script is quiet when all goes well and still says
+// what it was doing when something went wrong.
+func WithQuietUntilError(quiet bool) Option {
+	return func(s *Spinner) {
+		s.quiet = quiet
+	}
+}
+
+// WithForceAnimation keeps the spinner animating when TERM is "dumb". By
+// default a dumb terminal gets no escape sequences or carriage returns: Start
+// prints the prefix, message and suffix once on their own line and Stop
+// prints nothing.
+func WithForceAnimation(force bool) Option {
+	return func(s *Spinner) {
+		s.forceAnimation = force
+	}
+}
+
+// WithSignalHandling makes the spinner cooperate with job control on Unix
+// systems. On SIGTSTP (Ctrl-Z) it clears its line and shows the cursor before
+// the process stops, and on SIGCONT it hides the cursor again and resumes
+// drawing. It has no effect on other systems.
+func WithSignalHandling(enable bool) Option {
+	return func(s *Spinner) {
+		s.signalHandling = enable
+	}
+}
+
+// WithElapsed shows the time since Start, in whole seconds, after the
+// message and progress.
+func WithElapsed(show bool) Option {
+	return func(s *Spinner) {
+		s.elapsed = show
+	}
+}
+
+// WithSkipDuplicateFrames skips writing a frame that would look exactly like
+// the line already on screen, as happens with frame sets such as Material
+// that repeat frames to hold them. The animation still advances as usual;
+// only the redundant writes are left out.
+func WithSkipDuplicateFrames(skip bool) Option {
+	return func(s *Spinner) {
+		s.skipDuplicates = skip
+	}
+}
+
+// WithMinRenderInterval caps how often the spinner writes to at most once
+// every d, however short the interval func makes the time between frames.
+// The animation still advances on every tick; frames that come too soon
+// after the last one written are skipped. This keeps a fast animation, such
+// as SpeedupInterval near its end, from flooding slow terminals and remote
+// sessions. Zero, the default, writes every frame.
+func WithMinRenderInterval(d time.Duration) Option {
+	return func(s *Spinner) {
+		s.minRender = d
+	}
+}
+
+// WithRecorder appends every line drawn, without the leading carriage
+// return and padding, to *rec. The slice is written while the spinner runs,
+// so it should only be read after Stop or once Done is closed.
+func WithRecorder(rec *[]string) Option {
+	return func(s *Spinner) {
+		s.recorder = rec
 	}
 }
 
 var defaultFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 
 const (
-	hideCursorSeq = "\033[?25l"
-	showCursorSeq = "\033[?25h"
+	successSymbol = "✔"
+	failSymbol    = "✖"
+	warnSymbol    = "⚠"
 )
 
+// New returns a spinner configured by opts. It panics if the writer given
+// to WithWriter is nil; NewWithError reports that as an error instead.
 func New(opts ...Option) *Spinner {
+	s := newSpinner(opts)
+	for _, err := range s.optErrs {
+		if err == ErrNilWriter {
+			panic("spinner: WithWriter(nil): use io.Discard to discard the spinner's output")
+		}
+	}
+	if s.autoStart {
+		s.Start()
+	}
+	return s
+}
+
+// NewNoop returns a spinner that does nothing, for code that takes a
+// *Spinner but runs in tests or without a terminal. All of its methods can
+// be called as usual: Start never starts it, and anything it would print,
+// including final lines and Println output, is discarded. It stays a no-op
+// across Reset.
+func NewNoop() *Spinner {
+	return newSpinner([]Option{func(s *Spinner) { s.noop = true }})
+}
+
+// newSpinner is New without the check for a nil writer.
+func newSpinner(opts []Option) *Spinner {
 	s := &Spinner{
-		frames:     defaultFrames,
-		stop:       make(chan struct{}),
-		writer:     os.Stderr,
-		interval:   func() time.Duration { return 60 * time.Millisecond },
-		color:      func() string { return White },
-		hideCursor: true,
+		done: make(chan struct{}),
+		errs: make(chan error, 1),
 	}
+	s.configure(opts)
+	close(s.done)
+
+	return s
+}
+
+// configure sets every option back to its default and then applies opts.
+func (s *Spinner) configure(opts []Option) {
+	s.frames, s.rawFrames = defaultFrames, false
+	s.frameFunc, s.funcFrame = nil, ""
+	s.introFrames, s.outroFrames = nil, nil
+	s.playing, s.loopFrames = playLoop, nil
+	s.index, s.loops, s.ticks, s.rewind = 0, 0, 0, false
+	s.startedAt, s.lastErr = time.Time{}, nil
+	s.stoppedAt, s.starts, s.stops, s.totalTicks = time.Time{}, 0, 0, 0
+	s.slowFuncs = 0
+	s.writer = os.Stderr
+	s.writerLock = nil
+	s.interval = func() time.Duration { return 60 * time.Millisecond }
+	s.color = nil
+	s.colorMode = ColorAuto
+	s.background, s.detectBG = BackgroundUnknown, false
+	s.cursorMode = CursorHide
+	s.clearOnStop, s.stopNewline = true, false
+	s.stopSymbol, s.finalFrame = "", ""
+	s.successColor, s.failColor, s.warnColor = Green, Red, Yellow
+	s.successSymbol, s.failSymbol, s.warnSymbol = successSymbol, failSymbol, warnSymbol
+	s.messageColor = ""
+	s.skipDuplicates = false
+	s.minRender = 0
+	s.adaptMin, s.adaptMax, s.latency, s.lastInterval = 0, 0, 0, 0
+	s.driftCompensation = false
+	s.fixedInterval = 60 * time.Millisecond
+	s.intervalSource, s.intervalOpt = SourceDefault, ""
+	s.colorSource, s.colorOpt = SourceDefault, ""
+	s.optErrs, s.envErrs = nil, nil
+	s.envOverrides, s.disabled = false, false
+	s.autoStart = false
+	s.message, s.current, s.total = "", 0, 0
+	s.barWidth, s.determinate = 0, false
+	s.progressFormat = nil
+	s.percentFormat, s.durationFormat = FormatPercent, FormatDuration
+	s.pendingMessage.Store(nil)
+	s.pendingProgress.Store(nil)
+	s.rawMessages, s.newline = false, " "
+	s.maxLine, s.truncation = 0, TruncateTail
+	s.row, s.col = 0, 0
+	s.activityLines, s.activity, s.persistActivity = 0, nil, false
+	s.prefix, s.suffix, s.separator = "", "", " "
+	s.segments = nil
+	s.emojiWidth, s.strictFrames = defaultEmojiWidth, false
+	s.widthFunc = nil
+	s.oscProgress, s.oscPercent = false, -1
+	s.forceTTY, s.syncMode = false, SyncAuto
+	s.titleFormat, s.titleRestore = "", ""
+	s.plain, s.forceAnimation = false, false
+	s.testMode.Store(false)
+	s.quiet = false
+	s.signalHandling = false
+	s.pauseOnBlur = false
+	s.recorder = nil
+	s.jsonOut, s.jsonEvery = nil, time.Second
+	s.sink = nil
+	s.onStop = nil
+	s.elapsed = false
+	s.phases, s.phase = nil, -1
+	s.schedule, s.scheduled, s.overridePermanent = nil, -1, false
+	s.now = time.Now
+	s.loop = s.run
 
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.envOverrides {
+		s.applyEnv()
+	}
+	if s.writer == nil {
+		s.optErrs = append(s.optErrs, ErrNilWriter)
+		s.writer = io.Discard
+	}
+	if s.jsonOut != nil {
+		s.writer = s.jsonOut
+	}
+	if s.noop {
+		s.writer, s.disabled = io.Discard, true
+	}
+	s.message = s.sanitize(s.message)
+	s.tty = s.forceTTY || isTerminal(s.writer)
+	s.term = newTerm(s.writer, s.tty)
+	if s.term.dumb && s.forceAnimation {
+		s.term.ansi = s.tty
+	}
+	if s.color == nil {
+		bg := s.background
+		if (bg == BackgroundUnknown || bg == BackgroundAuto) && s.term.ansi {
+			bg, _ = parseCOLORFGBG(os.Getenv("COLORFGBG"))
+			s.detectBG = s.background == BackgroundAuto
+		}
+		color := defaultColor(bg)
+		s.color = func(FrameInfo) string { return color }
+	}
+	if s.strictFrames {
+		s.checkFrames(s.frames)
+	}
+	s.wrapFuncs()
+	s.setFrames(s.frames)
+	for _, seg := range s.segments {
+		seg.widths = s.frameWidths(seg.Frames)
+	}
+	s.sharedLock = s.writerLock == nil
+	if s.sharedLock {
+		s.writerLock = sharedWriterLock(s.writer, new(WriterLock))
+	}
+	s.columns = terminalWidth(s.writer)
+	if s.term.dumb && !s.forceAnimation || s.testMode.Load() {
+		s.plain = true
+		s.term.ansi = false
+		s.colorMode = ColorNever
+	}
+	if !s.term.ansi && isMultiline(s.frames) {
+		// Multiline frames are redrawn by moving the cursor up.
+		s.plain = true
+	}
+	if s.quiet {
+		s.plain = true
+	}
+	if s.jsonOut != nil || s.sink != nil {
+		// The spinner draws nothing itself, so nothing may touch the terminal.
+		s.plain, s.term.ansi = false, false
+		s.testMode.Store(false)
+		s.colorMode = ColorNever
+		s.oscProgress, s.signalHandling, s.pauseOnBlur = false, false, false
+		s.titleFormat = ""
+	}
+	if s.colorMode == ColorAuto {
+		s.colorMode = resolveColorMode(s.tty)
+	}
+	s.syncUpdates = s.term.ansi && !s.plain &&
+		(s.syncMode == SyncAlways || s.syncMode == SyncAuto && s.tty && syncSupported())
+	s.publish()
+}
 
-	return s
+// Reset stops the spinner if it is running and reconfigures it as if it had
+// just been returned by New with opts, starting it again if they include
+// WithAutoStart. Reusing a spinner this way avoids allocating a new one for
+// each of many short tasks. Any write error not yet received from Errors is
+// discarded.
+func (s *Spinner) Reset(opts ...Option) {
+	if s.reset(opts) {
+		s.Start()
+	}
+}
+
+// reset is Reset without the start. It reports whether opts asked for one.
+func (s *Spinner) reset(opts []Option) bool {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+	if s.halt("stopped", "", false) {
+		s.flushSafeWriters()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configure(opts)
+	select {
+	case <-s.errs:
+	default:
+	}
+	return s.autoStart
+}
+
+// WithAutoStart makes New, NewWithError and Reset return the spinner
+// already started, once every option has been applied, which saves a call
+// to Start. The spinner must still be stopped as usual, typically with
+// defer s.Stop(). It is off by default, so that constructing a spinner has
+// no side effects.
+func WithAutoStart(start bool) Option {
+	return func(s *Spinner) {
+		s.autoStart = start
+	}
 }
 
+// Start starts the animation. It does nothing if the spinner is already
+// running. Start and Stop may be called from any goroutine.
+//
+// A spinner can be started again after it stops, to show the phases of a
+// task one after another without allocating a spinner for each. Every run
+// begins at the first frame, with its own Done channel and Stats counters
+// for Ticks and Elapsed. Settings changed between runs, with SetFrames,
+// SetColor, UpdateMessage or SetProgress, carry over to the next run;
+// Reset goes back to the options given to New.
 func (s *Spinner) Start() {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
 	s.mu.Lock()
-	if s.active {
-		s.mu.Unlock()
+	defer s.mu.Unlock()
+	if s.state != stateIdle || s.disabled {
 		return
 	}
-	s.active = true
-	if s.hideCursor {
-		fmt.Fprint(s.writer, hideCursorSeq)
+	s.state = stateRunning
+	s.active.Store(true)
+	track(s)
+	s.holdWriterLock()
+	s.lastErr = nil
+	s.done = make(chan struct{})
+	s.startedAt, s.ticks, s.loops = s.now(), 0, 0
+	if s.rewind {
+		s.index, s.rewind = 0, false
 	}
-	s.mu.Unlock()
-
-	go func() {
-		for {
+	s.starts++
+	s.target, s.drift = time.Time{}, 0
+	s.lastDraw = time.Time{}
+	for _, seg := range s.segments {
+		seg.due = time.Time{}
+	}
+	if s.phases != nil {
+		s.phase = -1
+		s.applyPhase()
+	}
+	if s.schedule != nil {
+		if s.scheduled >= 0 {
+			s.message = s.baseMessage
+		}
+		s.scheduled, s.overridden = -1, false
+		s.applySchedule()
+	}
+	if len(s.introFrames) > 0 && !s.plain && s.segments == nil {
+		s.play(playIntro, s.introFrames)
+	}
+	s.writerLock.Lock()
+	defer s.writerLock.Unlock()
+	if s.plain {
+		// A quiet spinner draws no frames, so their height does not matter.
+		if isMultiline(s.frames) && !s.quiet {
+			s.lastErr = ErrMultilineFrames
 			select {
-			case <-s.stop:
-				return
+			case s.errs <- ErrMultilineFrames:
 			default:
-				s.mu.Lock()
-				fmt.Fprintf(s.writer, "\r%s%s%s", s.color(), s.frames[s.index], Reset)
-				s.index = (s.index + 1) % len(s.frames)
-				s.mu.Unlock()
-				time.Sleep(s.interval())
 			}
 		}
-	}()
+		if s.testMode.Load() {
+			s.testFrame()
+			return
+		}
+		s.applyMessage()
+		if line, _ := s.render(false); line != "" && !s.quiet {
+			fmt.Fprintln(s.writer, line)
+		}
+		s.publish()
+		return
+	}
+	if s.jsonOut != nil {
+		s.applyMessage()
+		s.writeEvent(s.event("start"))
+		s.lastDraw = s.startedAt
+	}
+	if s.detectBG && s.colorSource == SourceDefault {
+		color := defaultColor(autoBackground())
+		s.color = func(FrameInfo) string { return color }
+		s.cache = nil
+	}
+	s.detectBG = false
+	if s.cursorMode == CursorHide && s.term.ansi {
+		AcquireCursorHide(s.writer)
+		s.cursorHeld = true
+	}
+	if s.signalHandling {
+		go s.handleSignals(s.done)
+	}
+	s.blurred = false
+	s.setFocusReporting(true)
+	s.publish()
+	s.stop, s.exited = make(chan struct{}), make(chan struct{})
+	s.startEvaluator()
+	go s.loop(s.stop, s.exited)
+}
+
+// run draws frames until stop is closed or a write fails, and closes exited
+// when it returns. After a write failure it also closes done, so that Done
+// is never closed while the goroutine is still running.
+func (s *Spinner) run(stop <-chan struct{}, exited chan<- struct{}) {
+	done, onStop := s.done, s.onStop // set before Start started this goroutine
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			close(exited)
+			return
+		case <-timer.C:
+		}
+		wait, ok := s.tick()
+		if !ok {
+			close(exited)
+			close(done)
+			if onStop != nil {
+				onStop(s.Snapshot())
+			}
+			return
+		}
+		timer.Reset(wait)
+	}
+}
+
+// tick draws the next frame and returns how long to wait before drawing
+// again. It reports false if the spinner stopped itself.
+func (s *Spinner) tick() (time.Duration, bool) {
+	s.evalFuncs()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.countdown != nil {
+		s.updateCountdown()
+	}
+	if s.state != stateRunning || s.suspended || s.blurred {
+		s.target = time.Time{}
+		if s.playing == playOutro {
+			// A paused spinner draws nothing, so the outro is over.
+			s.sequenceEnded()
+		}
+		return s.interval(), true
+	}
+	now := s.now()
+	if s.target.IsZero() {
+		s.target = now
+	}
+	s.drift = now.Sub(s.target)
+	s.applyMessage()
+	if s.phases != nil {
+		s.applyPhase()
+	}
+	if s.schedule != nil {
+		s.applySchedule()
+	}
+	var wait time.Duration
+	if s.segments != nil {
+		wait = s.advanceSegments(s.now())
+	}
+	var (
+		line  string
+		w     int
+		write []byte // line as bytes to write as is, if prerendered
+	)
+	if s.static() {
+		line, w, write = s.prerender()
+	} else {
+		line, w = s.render(true)
+	}
+	if s.activityLines > 0 {
+		line, w = s.withActivity(line, w)
+	}
+	s.writerLock.Lock()
+	throttled := s.minRender > 0 && !s.lastDraw.IsZero() && now.Sub(s.lastDraw) < s.minRender
+	var err error
+	switch {
+	case s.jsonOut != nil:
+		if now.Sub(s.lastDraw) >= s.jsonEvery {
+			s.lastDraw = now
+			err = s.writeEvent(s.event("progress"))
+		}
+	case s.sink != nil:
+		if !throttled && (!s.skipDuplicates || line != s.lastLine) {
+			s.lastDraw, s.lastLine = now, line
+			s.sink.Render(s.sinkFrame(line))
+		}
+	case !throttled && (!s.skipDuplicates || line != s.lastLine):
+		s.lastDraw = now
+		start := s.now()
+		if write != nil && s.lastWidth <= w && s.lastHeight <= 1 {
+			_, err = s.writer.Write(write)
+		} else {
+			err = s.draw(line + padding(s.lastWidth-w))
+		}
+		if err != nil {
+			break
+		}
+		if s.adaptMax > 0 {
+			s.measureWrite(s.now().Sub(start))
+		}
+		s.lastWidth, s.lastLine = w, line
+		if s.recorder != nil {
+			*s.recorder = append(*s.recorder, line)
+		}
+	}
+	if err != nil {
+		s.writerLock.Unlock()
+		s.abort(err)
+		return 0, false
+	}
+	s.writeOSCProgress()
+	s.writeTitle(now)
+	s.writerLock.Unlock()
+	s.publish()
+	s.ticks++
+	s.totalTicks++
+	if s.segments != nil {
+		return wait, true
+	}
+	if n := len(s.frames); n > 0 {
+		s.index = (s.index + 1) % n
+		switch {
+		case s.index == 0 && s.playing != playLoop:
+			s.sequenceEnded()
+		case s.index == 0:
+			s.loops++
+		}
+	}
+	next := s.adapt(s.interval())
+	s.lastInterval = next
+	s.target = s.target.Add(next)
+	if !s.driftCompensation {
+		return next, true
+	}
+	wait = s.target.Sub(s.now())
+	if wait < -next {
+		// Too far behind to catch up: start a new schedule from the next frame.
+		s.target = s.target.Add(-wait)
+		wait = 0
+	}
+	return max(wait, 0), true
+}
+
+// frameInfo describes the frame about to be drawn.
+func (s *Spinner) frameInfo() FrameInfo {
+	return FrameInfo{
+		Index:           s.index,
+		Frames:          len(s.frames),
+		Loop:            s.loops,
+		Elapsed:         s.now().Sub(s.startedAt),
+		TicksSinceStart: s.ticks,
+		Current:         s.current,
+		Total:           s.total,
+	}
+}
+
+// render returns the current line and its display width. The prefix, frame
+// or progress bar, message, progress, elapsed time, any further segments and
+// the suffix are joined by the separator, skipping any that are empty.
+// Frames and segments are left out unless withFrames is set. With
+// WithMaxLineLength the message is truncated to keep the line within bounds.
+func (s *Spinner) render(withFrames bool) (string, int) {
+	line, w := s.renderLine(withFrames, s.message)
+	if s.maxLine > 0 && w > s.maxLine && s.message != "" {
+		room := s.width(s.message) - (w - s.maxLine)
+		line, w = s.renderLine(withFrames, s.truncate(s.message, room, s.truncation))
+	}
+	return line, w
+}
+
+// renderLine is render with msg in place of the spinner's message.
+func (s *Spinner) renderLine(withFrames bool, msg string) (string, int) {
+	type part struct {
+		text  string
+		width int
+	}
+	var progress string
+	switch {
+	case s.total <= 0:
+	case s.progressFormat != nil:
+		progress = s.progressFormat(fraction(s.current, s.total), s.now().Sub(s.startedAt))
+	default:
+		progress = s.percentFormat(s.current, s.total)
+	}
+	var elapsed string
+	if s.elapsed {
+		elapsed = s.durationFormat(s.now().Sub(s.startedAt))
+	}
+	message := part{msg, -1}
+	if msg != "" && s.messageColor != "" {
+		message = part{s.paint(s.messageColor, msg), s.plainWidth(msg)}
+	}
+	status := []part{message, {progress, -1}, {elapsed, -1}}
+	parts := []part{{s.prefix, -1}}
+	switch {
+	case !withFrames:
+		parts = append(parts, status...)
+	case s.determinate:
+		bar := progressBar(s.current, s.total, s.barWidth)
+		parts = append(parts, part{s.paint(s.color(s.frameInfo()), bar), s.barWidth})
+		parts = append(parts, status...)
+	case s.segments == nil && len(s.frames) == 0 && s.frameFunc == nil:
+		parts = append(parts, status...)
+	case s.segments == nil:
+		var frame string
+		var width int
+		if s.frameFunc != nil && s.playing == playLoop {
+			frame = s.frameFunc(s.frameInfo())
+			width = s.width(frame)
+			s.funcFrame = frame
+		} else {
+			frame, width = s.frames[s.index], s.widths[s.index]
+		}
+		if s.colorMode != ColorNever && !s.rawFrames {
+			frame = sgrSpan(s.color(s.frameInfo())) + frame + Reset
+		}
+		parts = append(parts, part{frame, width})
+		parts = append(parts, status...)
+	default:
+		frame, w := s.segmentFrame(s.segments[0])
+		parts = append(parts, part{frame, w})
+		parts = append(parts, status...)
+		for _, seg := range s.segments[1:] {
+			frame, w := s.segmentFrame(seg)
+			parts = append(parts, part{frame, w})
+		}
+	}
+	parts = append(parts, part{s.suffix, -1})
+	var b strings.Builder
+	w := 0
+	for _, p := range parts {
+		if p.text == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(s.separator)
+			w += s.plainWidth(s.separator)
+		}
+		b.WriteString(p.text)
+		if p.width < 0 {
+			p.width = s.plainWidth(p.text)
+		}
+		w += p.width
+	}
+	return b.String(), w
+}
+
+// SetFrames replaces the frames of the spinner and restarts the animation
+// from the first frame, once any intro or outro that is playing is over.
+func (s *Spinner) SetFrames(frames []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replaceFrames(frames)
+}
+
+// SetColor sets the color of the frames, as WithColor does, in place of any
+// color or color func set before.
+func (s *Spinner) SetColor(color string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.colorSource, s.userColor = SourceFixed, nil
+	s.color = func(FrameInfo) string { return color }
+	s.cache = nil
+}
+
+// MaxFrameWidth returns the display width of the widest frame, which callers
+// can use to reserve space for the spinner in a layout.
+func (s *Spinner) MaxFrameWidth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := 0
+	for _, fw := range s.widths {
+		w = max(w, fw)
+	}
+	return w
+}
+
+// Index returns the index of the frame that will be drawn next.
+func (s *Spinner) Index() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.index
+}
+
+// SetIndex sets the frame that will be drawn next. i is taken modulo the
+// number of frames, so negative values count back from the last frame. It
+// does nothing if the spinner has no frames.
+func (s *Spinner) SetIndex(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.frames); n > 0 {
+		s.index, s.rewind = (i%n+n)%n, false
+	}
 }
 
+// Stop stops the animation and shows the cursor again if it was hidden,
+// unless another holder of AcquireCursorHide on the same writer still wants
+// it hidden. Where the cursor ends up depends on the options:
+//
+//   - by default the line is erased and the cursor is left at its start;
+//   - with WithClearOnStop(false) the last line drawn stays on screen and the
+//     cursor is left just after it;
+//   - WithFinalNewline additionally moves the cursor to the start of the
+//     next line in either case.
+//
+// Success, Fail and Warn always replace the line and end with a newline.
 func (s *Spinner) Stop() {
+	s.end("stopped", func() string { return "" }, false, true)
+}
+
+// Stopf stops the spinner like Stop and prints a final line formatted as by
+// fmt.Sprintf, ending with a newline, in place of the spinner's line. With
+// WithClearOnStop(false) the spinner's last line is kept and the final line
+// is printed below it. The line starts with the WithStopSymbol symbol, if
+// one is set.
+func (s *Spinner) Stopf(format string, args ...any) {
+	s.end("stopped", func() string {
+		var color string
+		if s.stopSymbol != "" {
+			s.mu.Lock()
+			color = s.color(s.frameInfo())
+			s.mu.Unlock()
+		}
+		return s.finish(color, s.stopSymbol, fmt.Sprintf(format, args...))
+	}, false, true)
+}
+
+// WithStopSymbol sets a symbol that Stopf prints, in the spinner's color,
+// before its line.
+func WithStopSymbol(symbol string) Option {
+	return func(s *Spinner) {
+		s.stopSymbol = symbol
+	}
+}
+
+// WithFinalFrame sets a frame, such as "✔", that replaces the animation's
+// current frame when the spinner's line is left on screen by Stop or Stopf
+// with WithClearOnStop(false), so that the line comes to rest on a chosen
+// glyph rather than wherever the animation happened to be. Success, Fail
+// and Warn replace the line with their own symbol instead.
+func WithFinalFrame(frame string) Option {
+	return func(s *Spinner) {
+		s.finalFrame = frame
+	}
+}
+
+// drawFinalFrame redraws the spinner's line with the WithFinalFrame frame
+// in place of the current one.
+func (s *Spinner) drawFinalFrame() {
+	frames, widths, index, frameFunc := s.frames, s.widths, s.index, s.frameFunc
+	s.frames = []string{s.finalFrame}
+	s.widths = []int{s.width(s.finalFrame)}
+	s.index, s.frameFunc = 0, nil
+	line, w := s.render(true)
+	s.frames, s.widths, s.index, s.frameFunc = frames, widths, index, frameFunc
+	if s.draw(line+padding(s.lastWidth-w)) == nil {
+		s.lastWidth, s.lastLine = w, line
+	}
+}
+
+// Success stops the spinner and replaces its line with a check mark and msg,
+// or the latest message if msg is empty.
+func (s *Spinner) Success(msg string) {
+	s.end("success", func() string { return s.finish(s.successColor, s.successSymbol, msg) }, true, true)
+}
+
+// Fail is like Success but shows a cross.
+func (s *Spinner) Fail(msg string) {
+	s.end("fail", func() string { return s.finish(s.failColor, s.failSymbol, msg) }, true, true)
+}
+
+// Warn is like Success but shows a warning sign, for work that finished
+// with problems worth pointing out.
+func (s *Spinner) Warn(msg string) {
+	s.end("warn", func() string { return s.finish(s.warnColor, s.warnSymbol, msg) }, true, true)
+}
+
+// WithSuccessColor sets the color of the check mark Success prints. It
+// defaults to Green.
+func WithSuccessColor(color string) Option {
+	return func(s *Spinner) {
+		s.successColor = color
+	}
+}
+
+// WithFailColor sets the color of the cross Fail prints. It defaults to Red.
+func WithFailColor(color string) Option {
+	return func(s *Spinner) {
+		s.failColor = color
+	}
+}
+
+// WithWarnColor sets the color of the warning sign Warn prints. It defaults
+// to Yellow.
+func WithWarnColor(color string) Option {
+	return func(s *Spinner) {
+		s.warnColor = color
+	}
+}
+
+// end stops the spinner, leaving the line returned by final, and then calls
+// the WithOnStop hook if the spinner was running. status is the Event status
+// reported with WithJSONOutput. replace erases the spinner's line even with
+// WithClearOnStop(false), and outro plays the WithOutroFrames outro first.
+func (s *Spinner) end(status string, final func() string, replace, outro bool) {
+	s.lifecycle.Lock()
+	if outro {
+		s.playOutro()
+	}
+	s.mu.Lock()
+	s.endCountdown(false)
+	s.mu.Unlock()
+	stopped := s.halt(status, final(), replace)
+	if stopped {
+		s.flushSafeWriters()
+	}
+	onStop := s.onStop
+	s.lifecycle.Unlock()
+	if stopped && onStop != nil {
+		onStop(s.Snapshot())
+	}
+}
+
+// finish returns the line Success, Fail or Stopf leaves behind: symbol, if
+// there is one, and msg. An empty msg repeats the spinner's latest message.
+func (s *Spinner) finish(color, symbol, msg string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if msg == "" {
+		s.applyMessage()
+		msg = s.message
+	}
+	msg = s.sanitize(msg)
+	if s.jsonOut != nil {
+		return msg
+	}
+	if symbol == "" {
+		return msg + "\n"
+	}
+	return s.paint(color, symbol) + s.separator + msg + "\n"
+}
+
+// halt stops the animation and writes final. The line is cleared first if
+// replace is set or the spinner clears on stop; otherwise a non-empty final
+// is printed below it. With WithJSONOutput a done event with status and
+// final as its message is written instead. It reports whether the spinner
+// was running. The caller must hold lifecycle.
+func (s *Spinner) halt(status, final string, replace bool) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.active {
-		s.active = false
-		s.stop <- struct{}{}
-		fmt.Fprint(s.writer, "\r \r")
-		if s.hideCursor {
-			fmt.Fprint(s.writer, showCursorSeq)
+	s.idleWriterLock()
+	switch {
+	case s.jsonOut != nil && s.state != stateRunning:
+		if final != "" {
+			s.writerLock.Lock()
+			s.writeDone(status, final)
+			s.writerLock.Unlock()
+		}
+	case s.sink != nil && s.state != stateRunning:
+		if final != "" {
+			s.sink.Render(Frame{Message: strings.TrimSuffix(final, "\n"), Stopped: true})
 		}
+	case s.state != stateRunning || s.plain:
+		if s.quiet && status != "fail" {
+			break
+		}
+		s.writerLock.Lock()
+		fmt.Fprint(s.writer, final)
+		s.writerLock.Unlock()
+	}
+	if s.state != stateRunning {
+		return false
+	}
+	if s.plain {
+		s.state = stateIdle
+		s.active.Store(false)
+		untrack(s)
+		s.releaseWriterLock()
+		s.publishStopped()
+		close(s.done)
+		return true
+	}
+	s.state = stateStopping
+	s.suspended = false
+	s.endCountdown(false)
+	// Let the render goroutine finish its current tick and exit.
+	close(s.stop)
+	s.mu.Unlock()
+	<-s.exited
+	s.mu.Lock()
+	s.publishStopped()
+	s.writerLock.Lock()
+	defer s.writerLock.Unlock()
+
+	switch {
+	case s.jsonOut != nil:
+		s.writeDone(status, final)
+	case s.sink != nil:
+		f := s.sinkFrame(s.lastLine)
+		f.Message, f.Stopped = strings.TrimSuffix(final, "\n"), true
+		s.sink.Render(f)
+	default:
+		s.beginSync()
+		if s.finalFrame != "" && !s.clearOnStop && !replace && s.lastLine != "" && s.segments == nil {
+			s.drawFinalFrame()
+		}
+		switch {
+		case s.clearOnStop || replace:
+			s.clearLine()
+		case final != "" && s.lastLine != "":
+			fmt.Fprint(s.writer, "\n")
+		}
+		if s.persistActivity && (s.clearOnStop || replace) {
+			for _, a := range s.activity {
+				fmt.Fprintln(s.writer, a)
+			}
+		}
+		fmt.Fprint(s.writer, final)
+		if final == "" && s.stopNewline {
+			fmt.Fprint(s.writer, "\n")
+		}
+		s.endSync()
+	}
+	s.lastWidth, s.lastLine, s.lastHeight = 0, "", 0
+	s.rewind = true
+	s.activity = nil
+	if s.oscPercent >= 0 {
+		fmt.Fprint(s.writer, oscProgressClear)
+		s.oscPercent = -1
+	}
+	s.restoreTitle()
+	if s.cursorHeld {
+		ReleaseCursorHide(s.writer)
+		s.cursorHeld = false
+	}
+	s.setFocusReporting(false)
+	s.playLoopFrames()
+	s.state = stateIdle
+	s.active.Store(false)
+	untrack(s)
+	s.releaseWriterLock()
+	close(s.done)
+	return true
+}
+
+// suspend clears the line and restores the cursor, and stops drawing until
+// resume is called.
+func (s *Spinner) suspend() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stateRunning || s.suspended {
+		return
+	}
+	s.suspended = true
+	s.writerLock.Lock()
+	defer s.writerLock.Unlock()
+	s.clearLine()
+	s.lastWidth, s.lastLine, s.lastHeight = 0, "", 0
+	// The process is about to stop, so the cursor goes back on whoever
+	// else holds it hidden.
+	if s.cursorHeld {
+		s.term.ShowCursor()
+	}
+}
+
+// resume undoes suspend. The next tick repaints the line.
+func (s *Spinner) resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != stateRunning || !s.suspended {
+		return
+	}
+	s.suspended = false
+	if s.cursorHeld {
+		s.writerLock.Lock()
+		defer s.writerLock.Unlock()
+		s.term.HideCursor()
+	}
+}
+
+// abort stops the spinner after the writer failed. Nothing further is
+// written, since the writer is assumed to be unusable. The render goroutine
+// closes done once it has returned.
+func (s *Spinner) abort(err error) {
+	s.endCountdown(false)
+	s.playLoopFrames()
+	s.state = stateIdle
+	s.active.Store(false)
+	untrack(s)
+	s.releaseWriterLock()
+	s.lastErr = err
+	if s.cursorHeld {
+		releaseCursorHide(s.writer)
+		s.cursorHeld = false
+	}
+	s.publishStopped()
+	s.lastWidth, s.lastLine, s.lastHeight = 0, "", 0
+	s.rewind = true
+	select {
+	case s.errs <- err:
+	default:
+	}
+}
+
+// Errors returns a channel that receives the write error that caused the
+// spinner to stop on its own. If an earlier error has not been received yet,
+// later ones are dropped. The channel is never closed.
+func (s *Spinner) Errors() <-chan error {
+	return s.errs
+}
+
+// Done returns a channel that is closed once the spinner has stopped, its
+// render goroutine has returned and the terminal has been restored. Each
+// Start creates a new channel, so callers that restart a spinner must call
+// Done again. A spinner that has never been started returns an already
+// closed channel.
+func (s *Spinner) Done() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+// Wait blocks until the spinner has stopped or ctx is done.
+func (s *Spinner) Wait(ctx context.Context) error {
+	select {
+	case <-s.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
+// Color256 returns the escape sequence for color n of the 256-color
+// palette. Values outside 0 to 255 are clamped into that range; use
+// Color256Checked to have them reported instead.
 func Color256(n int) string {
+	return fmt.Sprintf("\033[38;5;%dm", min(max(n, 0), 255))
+}
+
+// Color256Checked is like Color256 but returns an error if n is not
+// between 0 and 255.
+func Color256Checked(n int) (string, error) {
 	if n < 0 || n > 255 {
-		return ""
+		return "", fmt.Errorf("spinner: 256-color index %d out of range 0 to 255", n)
+	}
+	return Color256(n), nil
+}
+
+// RGB returns the escape sequence for a 24-bit color. Components outside 0
+// to 255 are clamped into that range; use RGBChecked to have them reported
+// instead.
+func RGB(r, g, b int) string {
+	c := func(v int) int { return min(max(v, 0), 255) }
+	return fmt.Sprintf("\033[38;2;%d;%d;%dm", c(r), c(g), c(b))
+}
+
+// RGBChecked is like RGB but returns an error if a component is not between
+// 0 and 255.
+func RGBChecked(r, g, b int) (string, error) {
+	for _, v := range []int{r, g, b} {
+		if v < 0 || v > 255 {
+			return "", fmt.Errorf("spinner: RGB component %d out of range 0 to 255", v)
+		}
 	}
-	return fmt.Sprintf("\033[38;5;%dm", n)
+	return RGB(r, g, b), nil
 }
 
 const (
@@ -200,23 +1550,109 @@ var (
 
 // Helpers
 
+// GreyPulse returns a color func that fades between dark and light grey,
+// holding each shade for interval.
 func GreyPulse(interval time.Duration) func() string {
-	return ColorPulse(238, 255, interval)
+	var greys []string
+	for n := 238; n <= 255; n++ {
+		greys = append(greys, Color256(n))
+	}
+	return ColorPulseList(greys, interval*time.Duration(2*(len(greys)-1)))
 }
 
+// ColorCycle returns a color func for WithColorFunc that steps through
+// colors in order and starts over, giving each an equal share of period.
+// The color depends only on the time since ColorCycle was called.
+func ColorCycle(colors []string, period time.Duration) func() string {
+	return timedColors(colors, period, false, time.Now)
+}
+
+// ColorPulseList is like ColorCycle but walks back down the list after
+// reaching its end, so one period goes from the first color to the last and
+// back.
+func ColorPulseList(colors []string, period time.Duration) func() string {
+	return timedColors(colors, period, true, time.Now)
+}
+
+func timedColors(colors []string, period time.Duration, pingPong bool, now func() time.Time) func() string {
+	n := len(colors)
+	if n == 0 {
+		return func() string { return "" }
+	}
+	steps := n
+	if pingPong && n > 1 {
+		steps = 2 * (n - 1)
+	}
+	step := max(period/time.Duration(steps), 1)
+	start := now()
+	return func() string {
+		i := int(now().Sub(start)/step) % steps
+		if i >= n {
+			i = steps - i
+		}
+		return colors[i]
+	}
+}
+
+// LoopSyncedPulse returns a color func for WithColorFrameFunc that moves from
+// the 256-color start to end and back exactly once per pass through the
+// frame set.
+func LoopSyncedPulse(start, end int) func(FrameInfo) string {
+	return func(f FrameInfo) string {
+		if f.Frames <= 1 {
+			return Color256(start)
+		}
+		pos := 2 * float64(f.Index) / float64(f.Frames)
+		if pos > 1 {
+			pos = 2 - pos
+		}
+		return Color256(start + int(math.Round(pos*float64(end-start))))
+	}
+}
+
+// ColorThreshold is a color used once the spinner has run for After.
+type ColorThreshold struct {
+	After time.Duration
+	Color string
+}
+
+// ThresholdColor returns a color func for WithColorFrameFunc that uses the
+// color of the latest step whose After has passed, for example White, then
+// Yellow after 10s, then Red after 30s to show that a task is slow. Steps
+// should be given in order of After. Before the first step no color is set.
+func ThresholdColor(steps ...ColorThreshold) func(FrameInfo) string {
+	return func(f FrameInfo) string {
+		color := ""
+		for _, st := range steps {
+			if f.Elapsed >= st.After {
+				color = st.Color
+			}
+		}
+		return color
+	}
+}
+
+// ColorPulse returns a color func that steps through the 256-color range
+// from start to end and back, holding each color for duration.
 func ColorPulse(start, end int, duration time.Duration) func() string {
-	t := time.Now()
+	return colorPulse(start, end, duration, time.Now)
+}
+
+func colorPulse(start, end int, duration time.Duration, now func() time.Time) func() string {
+	t := now()
 	direction := 1
 	color := start
 	return func() string {
-		if time.Since(t) > duration {
-			t = time.Now()
+		if now().Sub(t) > duration {
+			t = now()
 			color += direction
-			if color > end {
+			// Turn at the ends rather than past them, so that start and end
+			// are held no longer than the colors between.
+			if color >= end {
 				color = end
 				direction = -1
 			}
-			if color < start {
+			if color <= start {
 				color = start
 				direction = 1
 			}
@@ -1,28 +1,46 @@
 package spinner_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
 	"time"
 
 	"github.com/tmc/spinner"
 )
 
 func ExampleSpinner_basic() {
-	s := spinner.New()
+	s := spinner.New(spinner.WithTestMode(true), spinner.WithWriter(os.Stdout), spinner.WithMessage("working"))
 	s.Start()
-	time.Sleep(time.Second)
+	s.UpdateMessage("still working")
 	s.Stop()
-	// output:
+	// Output:
+	// ⠋ working
+	// ⠙ still working
 }
 
 func ExampleSpinner_withCustomFrames() {
-	s := spinner.New(spinner.WithFrames(spinner.Dots12))
+	s := spinner.New(spinner.WithTestMode(true), spinner.WithWriter(os.Stdout), spinner.WithFrames(spinner.Dots12))
 	s.Start()
-	time.Sleep(3 * time.Second)
+	for i := 1; i <= 3; i++ {
+		s.UpdateMessage(fmt.Sprint("step ", i))
+	}
 	s.Stop()
-	// output:
+	// Output:
+	// ⢀⠀
+	// ⡀⠀ step 1
+	// ⠄⠀ step 2
+	// ⢂⠀ step 3
 }
+
 func ExampleSpinner_withCustomFramesAllDots() {
 	for i, f := range [][]string{
 		spinner.Dots1,
@@ -38,18 +56,30 @@ func ExampleSpinner_withCustomFramesAllDots() {
 		spinner.Dots11,
 		spinner.Dots12,
 	} {
-		s := spinner.New(spinner.WithFrames(f))
-		fmt.Fprintf(os.Stderr, "spinner.Dots%v\n", i+1)
+		s := spinner.New(spinner.WithTestMode(true), spinner.WithWriter(os.Stdout), spinner.WithFrames(f),
+			spinner.WithPrefix(fmt.Sprintf("spinner.Dots%v", i+1)))
 		s.Start()
-		time.Sleep(2 * time.Second)
 		s.Stop()
-		fmt.Fprintf(os.Stderr, "\n")
 	}
-	// output:
+	// Output:
+	// spinner.Dots1 ⠋
+	// spinner.Dots2 ⣾
+	// spinner.Dots3 ⠋
+	// spinner.Dots4 ⠄
+	// spinner.Dots5 ⠋
+	// spinner.Dots6 ⠁
+	// spinner.Dots7 ⠈
+	// spinner.Dots8 ⠁
+	// spinner.Dots9 ⢹
+	// spinner.Dots10 ⢄
+	// spinner.Dots11 ⠁
+	// spinner.Dots12 ⢀⠀
 }
 
 func ExampleSpinner_withAdvancedOptions() {
 	s := spinner.New(
+		spinner.WithTestMode(true),
+		spinner.WithWriter(os.Stdout),
 		spinner.WithFrames(spinner.Dots8),
 		spinner.WithIntervalFunc(
 			spinner.SpeedupInterval(90*time.Millisecond, 40*time.Millisecond, time.Second*5),
@@ -57,7 +87,814 @@ func ExampleSpinner_withAdvancedOptions() {
 		spinner.WithColorFunc(spinner.GreyPulse(15*time.Millisecond)),
 	)
 	s.Start()
-	time.Sleep(5 * time.Second)
+	s.UpdateMessage("faster")
+	s.UpdateMessage("fastest")
+	s.Stop()
+	// Output:
+	// ⠁
+	// ⠁ faster
+	// ⠉ fastest
+}
+
+func ExampleWithTestMode() {
+	s := spinner.New(
+		spinner.WithTestMode(true),
+		spinner.WithWriter(os.Stdout),
+		spinner.WithFrames(spinner.Line),
+		spinner.WithMessage("fetching"),
+	)
+	s.Start()
+	s.UpdateMessage("parsing")
+	s.SetProgress(1, 2)
+	s.SetProgress(2, 2)
+	s.Success("done")
+	// Output:
+	// - fetching
+	// \ parsing
+	// | parsing 50%
+	// / parsing 100%
+	// ✔ done
+}
+
+func TestSuccess(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithHideCursor(false), spinner.WithColorMode(spinner.ColorAlways))
+	s.Success("done")
+	if got, want := buf.String(), spinner.Green+"✔"+spinner.Reset+" done\n"; got != want {
+		t.Errorf("Success wrote %q, want %q", got, want)
+	}
+}
+
+func TestFail(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithHideCursor(false), spinner.WithColorMode(spinner.ColorAlways))
+	s.Start()
+	time.Sleep(100 * time.Millisecond)
+	s.Fail("boom")
+	out := buf.String()
+	if want := "\r \r" + spinner.Red + "✖" + spinner.Reset + " boom\n"; !strings.HasSuffix(out, want) {
+		t.Errorf("Fail output %q does not end with %q", out, want)
+	}
+}
+
+func TestQuietUntilError(t *testing.T) {
+	run := func(finish func(*spinner.Spinner)) string {
+		var buf bytes.Buffer
+		s := spinner.New(spinner.WithWriter(&buf), spinner.WithQuietUntilError(true), spinner.WithColorMode(spinner.ColorNever),
+			spinner.WithMessage("starting"))
+		if got := s.RenderState(); got != spinner.Suppressed {
+			t.Errorf("RenderState() = %v, want %v", got, spinner.Suppressed)
+		}
+		s.Start()
+		s.UpdateMessage("deploying")
+		time.Sleep(50 * time.Millisecond)
+		finish(s)
+		return buf.String()
+	}
+	for name, finish := range map[string]func(*spinner.Spinner){
+		"Success": func(s *spinner.Spinner) { s.Success("deployed") },
+		"Warn":    func(s *spinner.Spinner) { s.Warn("slow") },
+		"Stop":    (*spinner.Spinner).Stop,
+		"Stopf":   func(s *spinner.Spinner) { s.Stopf("done") },
+	} {
+		if out := run(finish); out != "" {
+			t.Errorf("%s wrote %q, want nothing", name, out)
+		}
+	}
+	if out, want := run(func(s *spinner.Spinner) { s.Fail("") }), "✖ deploying\n"; out != want {
+		t.Errorf("Fail wrote %q, want %q", out, want)
+	}
+
+	s := spinner.New(spinner.WithWriter(&bytes.Buffer{}), spinner.WithForceTTY(true), spinner.WithQuietUntilError(true),
+		spinner.WithFrames(spinner.TallBounce))
+	s.Start()
+	s.Stop()
+	select {
+	case err := <-s.Errors():
+		t.Errorf("quiet spinner with multiline frames reported %v", err)
+	default:
+	}
+}
+
+func TestFinisherColors(t *testing.T) {
+	palette := []spinner.Option{spinner.WithSuccessColor(spinner.Aqua), spinner.WithFailColor(spinner.Olive), spinner.WithWarnColor(spinner.Navy)}
+	tests := []struct {
+		name   string
+		opts   []spinner.Option
+		finish func(*spinner.Spinner)
+		want   string
+	}{
+		{"success", nil, func(s *spinner.Spinner) { s.Success("ok") }, spinner.Green + "✔" + spinner.Reset + " ok\n"},
+		{"fail", nil, func(s *spinner.Spinner) { s.Fail("no") }, spinner.Red + "✖" + spinner.Reset + " no\n"},
+		{"warn", nil, func(s *spinner.Spinner) { s.Warn("hm") }, spinner.Yellow + "⚠" + spinner.Reset + " hm\n"},
+		{"custom success", palette, func(s *spinner.Spinner) { s.Success("ok") }, spinner.Aqua + "✔" + spinner.Reset + " ok\n"},
+		{"custom fail", palette, func(s *spinner.Spinner) { s.Fail("no") }, spinner.Olive + "✖" + spinner.Reset + " no\n"},
+		{"custom warn", palette, func(s *spinner.Spinner) { s.Warn("hm") }, spinner.Navy + "⚠" + spinner.Reset + " hm\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			s := spinner.New(append([]spinner.Option{spinner.WithWriter(&buf), spinner.WithColorMode(spinner.ColorAlways)}, tt.opts...)...)
+			tt.finish(s)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoop(t *testing.T) {
+	s := spinner.NewNoop()
+	s.Start()
+	s.UpdateMessage("working")
+	s.SetProgress(1, 2)
+	s.Println("hidden")
+	if s.Snapshot().Active {
+		t.Error("no-op spinner is active after Start")
+	}
+	select {
+	case <-s.Done():
+	default:
+		t.Error("no-op spinner's Done channel is open")
+	}
+	s.Success("done")
+	var buf bytes.Buffer
+	s.Reset(spinner.WithWriter(&buf))
+	s.Start()
+	s.Fail("failed")
+	if s.RenderState() != spinner.Suppressed || buf.Len() != 0 {
+		t.Errorf("no-op spinner after Reset: RenderState %v, wrote %q", s.RenderState(), buf.String())
+	}
+}
+
+// recordingSpinner is a fake spinner.Spinners.
+type recordingSpinner struct {
+	calls  []string
+	active bool
+}
+
+func (r *recordingSpinner) Start()                 { r.calls, r.active = append(r.calls, "Start"), true }
+func (r *recordingSpinner) Stop()                  { r.calls, r.active = append(r.calls, "Stop"), false }
+func (r *recordingSpinner) UpdateMessage(m string) { r.calls = append(r.calls, "UpdateMessage "+m) }
+func (r *recordingSpinner) IsActive() bool         { return r.active }
+func (r *recordingSpinner) Success(m string) {
+	r.calls, r.active = append(r.calls, "Success "+m), false
+}
+func (r *recordingSpinner) Fail(m string) { r.calls, r.active = append(r.calls, "Fail "+m), false }
+
+func TestSpinnersInterface(t *testing.T) {
+	work := func(s spinner.Spinners) {
+		s.Start()
+		s.UpdateMessage("halfway")
+		if s.IsActive() {
+			s.Success("done")
+		}
+	}
+	fake := &recordingSpinner{}
+	work(fake)
+	if want := []string{"Start", "UpdateMessage halfway", "Success done"}; fmt.Sprint(fake.calls) != fmt.Sprint(want) {
+		t.Errorf("calls = %q, want %q", fake.calls, want)
+	}
+
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithColorMode(spinner.ColorNever))
+	work(s)
+	if s.IsActive() || !strings.Contains(buf.String(), "✔ done\n") {
+		t.Errorf("*Spinner: active %v, output %q", s.IsActive(), buf.String())
+	}
+	work(spinner.NewNoop())
+}
+
+func TestDoneRestart(t *testing.T) {
+	s := spinner.New(spinner.WithWriter(&bytes.Buffer{}))
+	select {
+	case <-s.Done():
+	default:
+		t.Fatal("Done not closed before first Start")
+	}
+	for i := 0; i < 2; i++ {
+		s.Start()
+		done := s.Done()
+		select {
+		case <-done:
+			t.Fatalf("run %d: Done closed while running", i)
+		default:
+		}
+		s.Stop()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("run %d: Done not closed after Stop", i)
+		}
+	}
+}
+
+func TestWait(t *testing.T) {
+	s := spinner.New(spinner.WithWriter(&bytes.Buffer{}))
+	s.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait on running spinner = %v, want %v", err, context.DeadlineExceeded)
+	}
+	go s.Stop()
+	if err := s.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait after Stop = %v", err)
+	}
+}
+
+func TestStopLineEnding(t *testing.T) {
+	frame := spinner.White + "-" + spinner.Reset
+	tests := []struct {
+		name    string
+		opts    []spinner.Option
+		finish  func(*spinner.Spinner)
+		wantEnd string
+	}{
+		{"default", nil, (*spinner.Spinner).Stop, frame + "\r \r"},
+		{"newline", []spinner.Option{spinner.WithStopNewline(true)}, (*spinner.Spinner).Stop, frame + "\r \r\n"},
+		{"persist", []spinner.Option{spinner.WithClearOnStop(false)}, (*spinner.Spinner).Stop, frame},
+		{"persist newline", []spinner.Option{spinner.WithClearOnStop(false), spinner.WithStopNewline(true)}, (*spinner.Spinner).Stop, frame + "\n"},
+		{"success newline", []spinner.Option{spinner.WithStopNewline(true)}, func(s *spinner.Spinner) { s.Success("ok") }, "\r \r" + spinner.Green + "✔" + spinner.Reset + " ok\n"},
+		{"success persist", []spinner.Option{spinner.WithClearOnStop(false)}, func(s *spinner.Spinner) { s.Success("ok") }, "\r \r" + spinner.Green + "✔" + spinner.Reset + " ok\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := append([]spinner.Option{
+				spinner.WithWriter(&buf),
+				spinner.WithHideCursor(false),
+				spinner.WithColorMode(spinner.ColorAlways),
+				spinner.WithFrames([]string{"-"}),
+			}, tt.opts...)
+			s := spinner.New(opts...)
+			s.Start()
+			time.Sleep(100 * time.Millisecond)
+			tt.finish(s)
+			if got := buf.String(); !strings.HasSuffix(got, tt.wantEnd) {
+				t.Errorf("output %q does not end with %q", got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var frames []string
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithFrames(spinner.Line),
+		spinner.WithColor(spinner.Red),
+		spinner.WithColorMode(spinner.ColorAlways),
+		spinner.WithInterval(5*time.Millisecond),
+		spinner.WithRecorder(&frames),
+	)
+	s.Start()
+	time.Sleep(100 * time.Millisecond)
+	s.Stop()
+	if len(frames) < len(spinner.Line) {
+		t.Fatalf("recorded %d frames, want at least %d", len(frames), len(spinner.Line))
+	}
+	for i, got := range frames {
+		if want := spinner.Red + spinner.Line[i%len(spinner.Line)] + spinner.Reset; got != want {
+			t.Errorf("frame %d = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestClosedWriter(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	go io.Copy(io.Discard, r)
+
+	s := spinner.New(spinner.WithWriter(w), spinner.WithInterval(time.Millisecond))
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	w.Close()
+
+	select {
+	case err := <-s.Errors():
+		if !errors.Is(err, os.ErrClosed) {
+			t.Errorf("Errors() = %v, want %v", err, os.ErrClosed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no error reported after writer was closed")
+	}
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second):
+		t.Fatal("spinner did not stop after write error")
+	}
 	s.Stop()
-	// output:
+}
+
+// failAfter is a writer that fails once n writes have succeeded.
+type failAfter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (w *failAfter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.n == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestDoneAfterRenderExit(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		n := 1 << 30
+		if fail {
+			n = 3
+		}
+		s := spinner.New(spinner.WithWriter(&failAfter{n: n}), spinner.WithInterval(time.Millisecond))
+		s.Start()
+		exited := spinner.RenderExited(s)
+		if !fail {
+			time.Sleep(10 * time.Millisecond)
+			s.Stop()
+		}
+		select {
+		case <-s.Done():
+		case <-time.After(time.Second):
+			t.Fatalf("fail=%v: Done not closed", fail)
+		}
+		select {
+		case <-exited:
+		default:
+			t.Errorf("fail=%v: Done closed before the render goroutine returned", fail)
+		}
+	}
+}
+
+func TestSeparator(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []spinner.Option
+		want string
+	}{
+		{"frame only", nil, "-"},
+		{"default", []spinner.Option{spinner.WithPrefix("[build]"), spinner.WithSuffix("(1/3)")}, "[build] - (1/3)"},
+		{"empty", []spinner.Option{spinner.WithPrefix("["), spinner.WithSuffix("]"), spinner.WithSeparator("")}, "[-]"},
+		{"custom", []spinner.Option{spinner.WithPrefix("a"), spinner.WithSuffix("b"), spinner.WithSeparator(" | ")}, "a | - | b"},
+		{"prefix only", []spinner.Option{spinner.WithPrefix("a"), spinner.WithSeparator(":")}, "a:-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var frames []string
+			var buf bytes.Buffer
+			opts := append([]spinner.Option{
+				spinner.WithWriter(&buf),
+				spinner.WithHideCursor(false),
+				spinner.WithColorMode(spinner.ColorNever),
+				spinner.WithFrames([]string{"-"}),
+				spinner.WithRecorder(&frames),
+			}, tt.opts...)
+			s := spinner.New(opts...)
+			s.Start()
+			time.Sleep(50 * time.Millisecond)
+			s.Stop()
+			if len(frames) == 0 || frames[0] != tt.want {
+				t.Fatalf("rendered %q, want %q", frames, tt.want)
+			}
+			if want := "\r" + strings.Repeat(" ", len(tt.want)) + "\r"; !strings.HasSuffix(buf.String(), want) {
+				t.Errorf("output %q does not clear with %q", buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestConcurrentStartStop(t *testing.T) {
+	// settle waits for the goroutine count to satisfy ok and returns it.
+	settle := func(ok func(n int) bool) int {
+		deadline := time.Now().Add(time.Second)
+		for {
+			n := runtime.NumGoroutine()
+			if ok(n) || time.Now().After(deadline) {
+				return n
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	before := runtime.NumGoroutine()
+	s := spinner.New(spinner.WithWriter(io.Discard), spinner.WithInterval(time.Millisecond))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 200; j++ {
+				switch r.Intn(3) {
+				case 0:
+					s.Start()
+				case 1:
+					s.Stop()
+				case 2:
+					s.Success("done")
+				}
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+
+	s.Stop()
+	idle := settle(func(n int) bool { return n <= before })
+	if idle > before {
+		t.Fatalf("%d goroutines after Stop, want at most %d", idle, before)
+	}
+	s.Start()
+	s.Start()
+	if n := settle(func(n int) bool { return n == idle+1 }); n != idle+1 {
+		t.Fatalf("%d goroutines while running, want %d", n, idle+1)
+	}
+	s.Stop()
+	s.Stop()
+	if n := settle(func(n int) bool { return n <= idle }); n > idle {
+		t.Fatalf("%d goroutines after final Stop, want %d", n, idle)
+	}
+}
+
+func TestSetIndex(t *testing.T) {
+	s := spinner.New(spinner.WithFrames(spinner.Line))
+	for _, tt := range []struct{ set, want int }{
+		{0, 0}, {2, 2}, {4, 0}, {9, 1}, {-1, 3}, {-4, 0}, {-6, 2},
+	} {
+		s.SetIndex(tt.set)
+		if got := s.Index(); got != tt.want {
+			t.Errorf("SetIndex(%d): Index() = %d, want %d", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestSetIndexSync(t *testing.T) {
+	var a, b []string
+	sa := spinner.New(spinner.WithWriter(io.Discard), spinner.WithColorMode(spinner.ColorNever), spinner.WithRecorder(&a), spinner.WithInterval(time.Hour))
+	sb := spinner.New(spinner.WithWriter(io.Discard), spinner.WithColorMode(spinner.ColorNever), spinner.WithRecorder(&b), spinner.WithInterval(time.Hour))
+	sa.SetIndex(7)
+	sb.SetIndex(sa.Index())
+	sa.Start()
+	sb.Start()
+	time.Sleep(20 * time.Millisecond)
+	sa.Stop()
+	sb.Stop()
+	if len(a) != 1 || len(b) != 1 || a[0] != spinner.Dots1[7] || b[0] != a[0] {
+		t.Errorf("spinners drew %q and %q, want both %q", a, b, spinner.Dots1[7])
+	}
+}
+
+func TestStopCursorPosition(t *testing.T) {
+	const (
+		hide  = "\033[?25l"
+		show  = "\033[?25h"
+		frame = "\r-- msg"
+		clear = "\r      \r"
+	)
+	stop := (*spinner.Spinner).Stop
+	success := func(s *spinner.Spinner) { s.Success("ok") }
+	fail := func(s *spinner.Spinner) { s.Fail("no") }
+	stopf := func(s *spinner.Spinner) { s.Stopf("%d files", 3) }
+	tests := []struct {
+		name           string
+		clear, newline bool
+		finish         func(*spinner.Spinner)
+		want           string
+	}{
+		{"Stop", true, false, stop, hide + frame + clear + show},
+		{"Stop newline", true, true, stop, hide + frame + clear + "\n" + show},
+		{"Stop persist", false, false, stop, hide + frame + show},
+		{"Stop persist newline", false, true, stop, hide + frame + "\n" + show},
+		{"Success", true, false, success, hide + frame + clear + "✔ ok\n" + show},
+		{"Success newline", true, true, success, hide + frame + clear + "✔ ok\n" + show},
+		{"Success persist", false, false, success, hide + frame + clear + "✔ ok\n" + show},
+		{"Fail persist newline", false, true, fail, hide + frame + clear + "✖ no\n" + show},
+		{"Stopf", true, false, stopf, hide + frame + clear + "3 files\n" + show},
+		{"Stopf persist", false, false, stopf, hide + frame + "\n3 files\n" + show},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			s := spinner.New(
+				spinner.WithWriter(&buf),
+				spinner.WithForceTTY(true),
+				spinner.WithColorMode(spinner.ColorNever),
+				spinner.WithFrames([]string{"--"}),
+				spinner.WithSuffix("msg"),
+				spinner.WithInterval(time.Hour),
+				spinner.WithClearOnStop(tt.clear),
+				spinner.WithFinalNewline(tt.newline),
+			)
+			s.Start()
+			time.Sleep(20 * time.Millisecond)
+			tt.finish(s)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStopfSymbol(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithColorMode(spinner.ColorAlways), spinner.WithColor(spinner.Blue), spinner.WithStopSymbol("•"))
+	s.Stopf("processed %d files in %s", 12, 2*time.Second)
+	if got, want := buf.String(), spinner.Blue+"•"+spinner.Reset+" processed 12 files in 2s\n"; got != want {
+		t.Errorf("Stopf wrote %q, want %q", got, want)
+	}
+}
+
+func TestRawFrames(t *testing.T) {
+	frames := []string{spinner.Red + "-" + spinner.Reset, spinner.Lime + "=" + spinner.Blue + "=" + spinner.Reset}
+	var lines []string
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorAlways),
+		spinner.WithColor(spinner.Aqua),
+		spinner.WithFrames(frames),
+		spinner.WithRawFrames(true),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithRecorder(&lines),
+	)
+	if got := s.MaxFrameWidth(); got != 2 {
+		t.Errorf("MaxFrameWidth = %d, want 2", got)
+	}
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+	if len(lines) < 2 {
+		t.Fatalf("got %d lines, want at least 2", len(lines))
+	}
+	for i, line := range lines {
+		if want := frames[i%2]; line != want {
+			t.Errorf("line %d = %q, want the frame verbatim %q", i, line, want)
+		}
+	}
+}
+
+func TestColorFrameFunc(t *testing.T) {
+	var infos []spinner.FrameInfo
+	s := spinner.New(
+		spinner.WithWriter(io.Discard),
+		spinner.WithFrames(spinner.Line),
+		spinner.WithInterval(2*time.Millisecond),
+		spinner.WithColorMode(spinner.ColorAlways),
+		spinner.WithColorFrameFunc(func(f spinner.FrameInfo) string {
+			infos = append(infos, f)
+			return spinner.Red
+		}),
+	)
+	s.SetIndex(2)
+	s.Start()
+	time.Sleep(50 * time.Millisecond)
+	s.Stop()
+
+	if len(infos) < 2*len(spinner.Line) {
+		t.Fatalf("color func called %d times, want at least %d", len(infos), 2*len(spinner.Line))
+	}
+	for i, f := range infos {
+		want := spinner.FrameInfo{
+			Index:           (2 + i) % 4,
+			Frames:          4,
+			Loop:            (2 + i) / 4,
+			Elapsed:         f.Elapsed,
+			TicksSinceStart: i,
+		}
+		if f != want {
+			t.Fatalf("call %d: FrameInfo = %+v, want %+v", i, f, want)
+		}
+		if i > 0 && f.Elapsed < infos[i-1].Elapsed {
+			t.Errorf("call %d: Elapsed went backwards", i)
+		}
+	}
+}
+
+func TestLoopSyncedPulse(t *testing.T) {
+	pulse := spinner.LoopSyncedPulse(240, 248)
+	want := []string{
+		spinner.Color256(240),
+		spinner.Color256(244),
+		spinner.Color256(248),
+		spinner.Color256(244),
+	}
+	for loop := 0; loop < 2; loop++ {
+		for i, w := range want {
+			if got := pulse(spinner.FrameInfo{Index: i, Frames: len(want), Loop: loop}); got != w {
+				t.Errorf("loop %d frame %d: color %q, want %q", loop, i, got, w)
+			}
+		}
+	}
+}
+
+func TestDumbTerminal(t *testing.T) {
+	t.Setenv("TERM", "dumb")
+	t.Setenv("FORCE_COLOR", "1")
+
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithPrefix("building"), spinner.WithInterval(time.Millisecond))
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	s.Success("built")
+	if got, want := buf.String(), "building\n✔ built\n"; got != want {
+		t.Errorf("dumb terminal output = %q, want %q", got, want)
+	}
+	select {
+	case <-s.Done():
+	default:
+		t.Error("Done not closed after Success")
+	}
+
+	buf.Reset()
+	s = spinner.New(spinner.WithWriter(&buf), spinner.WithForceTTY(true), spinner.WithForceAnimation(true), spinner.WithInterval(time.Millisecond))
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+	if out := buf.String(); !strings.Contains(out, "\033[?25l") || !strings.Contains(out, "\r"+spinner.White) {
+		t.Errorf("forced animation output = %q, want cursor hiding and colored frames", out)
+	}
+}
+
+func TestRestartCycles(t *testing.T) {
+	before := runtime.NumGoroutine()
+	s := spinner.New(spinner.WithWriter(io.Discard), spinner.WithInterval(time.Microsecond))
+	for i := 0; i < 100; i++ {
+		s.Start()
+		done := s.Done()
+		if i%2 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		s.Stop()
+		select {
+		case <-done:
+		default:
+			t.Fatalf("cycle %d: Done not closed after Stop", i)
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines after 100 cycles, want at most %d", n, before)
+	}
+}
+
+func TestReset(t *testing.T) {
+	var first, second []string
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithFrames([]string{"x"}),
+		spinner.WithMessage("first"),
+		spinner.WithSeparator("|"),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithRecorder(&first),
+	)
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.Reset(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithMessage("second"),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithRecorder(&second),
+	)
+	select {
+	case <-s.Done():
+	default:
+		t.Fatal("Reset did not stop the running spinner")
+	}
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+
+	if len(first) == 0 || first[0] != "x|first" {
+		t.Errorf("first run lines = %q", first)
+	}
+	if len(second) == 0 || second[0] != "⠋ second" {
+		t.Errorf("after Reset lines = %q, want default frames and separator", second)
+	}
+}
+
+// Run with -benchtime=10000x to time 10k start/stop cycles.
+func BenchmarkStartStop(b *testing.B) {
+	opts := []spinner.Option{spinner.WithWriter(io.Discard), spinner.WithMessage("working")}
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s := spinner.New(opts...)
+			s.Start()
+			s.Stop()
+		}
+	})
+	b.Run("Reset", func(b *testing.B) {
+		b.ReportAllocs()
+		s := spinner.New(opts...)
+		for i := 0; i < b.N; i++ {
+			s.Reset(opts...)
+			s.Start()
+			s.Stop()
+		}
+	})
+}
+
+func TestUpdateMessage(t *testing.T) {
+	var buf bytes.Buffer
+	var lines []string
+	s := spinner.New(
+		spinner.WithWriter(&buf),
+		spinner.WithHideCursor(false),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithFrames([]string{"-"}),
+		spinner.WithInterval(time.Millisecond),
+		spinner.WithRecorder(&lines),
+	)
+	s.Start()
+	for i := 0; i < 1000; i++ {
+		s.UpdateMessage(fmt.Sprint("step ", i))
+	}
+	time.Sleep(10 * time.Millisecond)
+	s.UpdateMessage("last")
+	s.Success("")
+
+	if n := len(lines); n == 0 || n > 50 {
+		t.Fatalf("recorded %d lines for 1000 updates", n)
+	}
+	for _, line := range lines {
+		if line != "- step 999" && line != "-" {
+			t.Errorf("line %q shows a message other than the latest", line)
+		}
+	}
+	if out := buf.String(); !strings.HasSuffix(out, "✔ last\n") {
+		t.Errorf("output ends %q, want the message set just before Success", out[max(0, len(out)-20):])
+	}
+}
+
+func TestAutoStart(t *testing.T) {
+	s := spinner.New(spinner.WithWriter(io.Discard))
+	if s.IsActive() {
+		t.Error("spinner active without WithAutoStart")
+	}
+	s = spinner.New(spinner.WithWriter(io.Discard), spinner.WithAutoStart(true), spinner.WithMessage("working"))
+	defer s.Stop()
+	if !s.IsActive() {
+		t.Error("New with WithAutoStart returned an idle spinner")
+	}
+	if got := s.Snapshot().Message; got != "working" {
+		t.Errorf("message = %q, want the one from the option after WithAutoStart", got)
+	}
+	s.Reset(spinner.WithWriter(io.Discard))
+	if s.IsActive() {
+		t.Error("spinner active after Reset without WithAutoStart")
+	}
+	s.Reset(spinner.WithWriter(io.Discard), spinner.WithAutoStart(true))
+	if !s.IsActive() {
+		t.Error("Reset with WithAutoStart left the spinner idle")
+	}
+	if _, err := spinner.NewWithError(spinner.WithInterval(time.Second), spinner.WithIntervalFunc(nil), spinner.WithAutoStart(true)); err == nil {
+		t.Error("NewWithError accepted conflicting options")
+	}
+	s2, err := spinner.NewWithError(spinner.WithWriter(io.Discard), spinner.WithAutoStart(true))
+	if err != nil || !s2.IsActive() {
+		t.Errorf("NewWithError with WithAutoStart: active %v, err %v", s2.IsActive(), err)
+	}
+	s2.Stop()
+}
+
+// Run with -race: IsActive reads the running state without the spinner's
+// lock while Start and Stop change it.
+func TestIsActiveConcurrent(t *testing.T) {
+	s := spinner.New(spinner.WithWriter(io.Discard), spinner.WithInterval(time.Millisecond))
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					s.IsActive()
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		s.Start()
+		if !s.IsActive() {
+			t.Fatalf("cycle %d: inactive after Start", i)
+		}
+		s.Stop()
+		if s.IsActive() {
+			t.Fatalf("cycle %d: active after Stop", i)
+		}
+	}
+	close(stop)
+	wg.Wait()
 }
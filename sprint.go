@@ -0,0 +1,57 @@
+package spinner
+
+import "strings"
+
+// A RenderOption configures Sprint.
+type RenderOption func(*renderConfig)
+
+type renderConfig struct {
+	color, prefix, suffix string
+}
+
+// RenderColor colors the frame drawn by Sprint, followed by Reset.
+func RenderColor(color string) RenderOption {
+	return func(c *renderConfig) {
+		c.color = color
+	}
+}
+
+// RenderPrefix sets text that Sprint places before the frame.
+func RenderPrefix(prefix string) RenderOption {
+	return func(c *renderConfig) {
+		c.prefix = prefix
+	}
+}
+
+// RenderSuffix sets text that Sprint places after the frame.
+func RenderSuffix(suffix string) RenderOption {
+	return func(c *renderConfig) {
+		c.suffix = suffix
+	}
+}
+
+// Sprint returns frame index of frames, with the prefix and suffix set by
+// opts joined to it by spaces, as a spinner would draw it. index is taken
+// modulo the number of frames, so a caller that animates spinners itself,
+// such as in the cells of a table, can pass a counter that keeps growing.
+// Sprint keeps no state and writes nothing.
+func Sprint(frames []string, index int, opts ...RenderOption) string {
+	var c renderConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	var frame string
+	if n := len(frames); n > 0 {
+		frame = frames[(index%n+n)%n]
+	}
+	if c.color != "" && frame != "" {
+		frame = sgrSpan(c.color) + frame + Reset
+	}
+	var parts []string
+	for _, p := range []string{c.prefix, frame, c.suffix} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
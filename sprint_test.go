@@ -0,0 +1,41 @@
+package spinner_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tmc/spinner"
+)
+
+func ExampleSprint() {
+	for tick := 0; tick < 3; tick++ {
+		fmt.Printf("| %-12s |\n", spinner.Sprint(spinner.Line, tick, spinner.RenderSuffix("syncing")))
+	}
+	// Output:
+	// | - syncing    |
+	// | \ syncing    |
+	// | | syncing    |
+}
+
+func TestSprint(t *testing.T) {
+	frames := []string{"a", "b", "c"}
+	tests := []struct {
+		index int
+		opts  []spinner.RenderOption
+		want  string
+	}{
+		{0, nil, "a"},
+		{4, nil, "b"},
+		{-1, nil, "c"},
+		{0, []spinner.RenderOption{spinner.RenderColor(spinner.Red)}, spinner.Red + "a" + spinner.Reset},
+		{1, []spinner.RenderOption{spinner.RenderPrefix("["), spinner.RenderSuffix("] job")}, "[ b ] job"},
+	}
+	for _, tt := range tests {
+		if got := spinner.Sprint(frames, tt.index, tt.opts...); got != tt.want {
+			t.Errorf("Sprint(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+	if got := spinner.Sprint(nil, 3, spinner.RenderSuffix("idle")); got != "idle" {
+		t.Errorf("Sprint with no frames = %q, want %q", got, "idle")
+	}
+}
@@ -0,0 +1,63 @@
+package spinner
+
+import "time"
+
+// Stats are counters describing what a spinner has done. Ticks, Elapsed
+// and Drift cover the latest run, from Start to Stop or to now if the
+// spinner is still running. TotalTicks, Starts and Stops count from New or
+// Reset and only ever grow, so they suit metrics counters; a daemon can
+// watch TotalTicks advance as a sign that its spinner, and the work it
+// reports on, are alive.
+type Stats struct {
+	Ticks   int           // frames drawn
+	Elapsed time.Duration // time running
+	Drift   time.Duration // how late the last frame was against its schedule
+
+	// Interval is the wait after the latest frame, as stretched by
+	// WithAdaptiveInterval, and WriteLatency the smoothed time a frame
+	// takes to write, measured only with WithAdaptiveInterval.
+	Interval     time.Duration
+	WriteLatency time.Duration
+
+	TotalTicks int // frames drawn across all runs
+	Starts     int // runs started
+	Stops      int // runs ended, by Stop, Success, Fail or a write error
+	SlowFuncs  int // color or interval func calls that took over an interval
+}
+
+// Stats returns the spinner's current Stats.
+func (s *Spinner) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Stats{
+		Ticks:        s.ticks,
+		Drift:        s.drift,
+		Interval:     s.lastInterval,
+		WriteLatency: s.latency,
+		TotalTicks:   s.totalTicks,
+		Starts:       s.starts,
+		Stops:        s.stops,
+		SlowFuncs:    s.slowFuncs,
+	}
+	switch {
+	case s.state != stateIdle:
+		st.Elapsed = s.now().Sub(s.startedAt)
+	case !s.startedAt.IsZero():
+		st.Elapsed = s.stoppedAt.Sub(s.startedAt)
+	}
+	return st
+}
+
+// WithDriftCompensation schedules each frame against the start of the
+// animation, sleeping until the frame's due time rather than for a fixed
+// interval after the previous one. Time spent drawing, such as a slow write
+// to a busy terminal, then no longer pushes every later frame back, so the
+// animation stays in step with the clock over long runs. A spinner that
+// falls more than an interval behind starts a new schedule instead of
+// drawing a burst of frames to catch up. It has no effect with
+// WithSegments, whose segments keep their own schedules.
+func WithDriftCompensation(enable bool) Option {
+	return func(s *Spinner) {
+		s.driftCompensation = enable
+	}
+}
@@ -0,0 +1,101 @@
+package spinner
+
+import (
+	"testing"
+	"time"
+)
+
+// slowWriter advances a fake clock by delay on the next Write, as a slow
+// terminal would.
+type slowWriter struct {
+	clock *fakeClock
+	delay time.Duration
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	w.clock.t = w.clock.t.Add(w.delay)
+	w.delay = 0
+	return len(p), nil
+}
+
+func TestDriftCompensation(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	for _, tt := range []struct {
+		compensate bool
+		drift      time.Duration
+	}{
+		{false, 250 * time.Millisecond},
+		{true, 0},
+	} {
+		clock := &fakeClock{time.Unix(0, 0)}
+		w := &slowWriter{clock: clock}
+		s := New(WithWriter(w), WithInterval(60*time.Millisecond), WithDriftCompensation(tt.compensate))
+		s.now = clock.now
+		s.loop = manualLoop
+		s.Start()
+		start := clock.t
+		for i := 0; i < 100; i++ {
+			if i%10 == 3 {
+				w.delay = 25 * time.Millisecond
+			}
+			wait, _ := s.tick()
+			clock.t = clock.t.Add(wait) // sleeping exactly as asked
+		}
+		st := s.Stats()
+		s.Stop()
+		if st.Ticks != 100 || st.Drift != tt.drift {
+			t.Errorf("compensate=%v: Stats = %+v, want 100 ticks and drift %v", tt.compensate, st, tt.drift)
+		}
+		if got := clock.t.Sub(start); tt.compensate && got != 6*time.Second {
+			t.Errorf("100 frames at 60ms took %v with compensation, want 6s", got)
+		}
+	}
+
+	// A stall longer than an interval starts a new schedule.
+	clock := &fakeClock{time.Unix(0, 0)}
+	w := &slowWriter{clock: clock}
+	s := New(WithWriter(w), WithInterval(60*time.Millisecond), WithDriftCompensation(true))
+	s.now = clock.now
+	s.loop = manualLoop
+	s.Start()
+	s.tick()
+	w.delay = time.Second
+	if wait, _ := s.tick(); wait != 0 {
+		t.Errorf("wait after a 1s stall = %v, want 0", wait)
+	}
+	if wait, _ := s.tick(); wait != 60*time.Millisecond {
+		t.Errorf("wait once rescheduled = %v, want 60ms", wait)
+	}
+	s.Stop()
+}
+
+func TestStatsCounters(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	clock := &fakeClock{time.Unix(0, 0)}
+	s := New(WithWriter(&countingWriter{}), WithInterval(100*time.Millisecond))
+	s.now, s.loop = clock.now, manualLoop
+	run := func(ticks int) {
+		s.Start()
+		for i := 0; i < ticks; i++ {
+			clock.t = clock.t.Add(100 * time.Millisecond)
+			s.tick()
+		}
+	}
+
+	if got := s.Stats(); got != (Stats{}) {
+		t.Errorf("Stats before Start = %+v, want zero", got)
+	}
+	run(3)
+	s.Stop()
+	clock.t = clock.t.Add(time.Hour)
+	want := Stats{Ticks: 3, Elapsed: 300 * time.Millisecond, Interval: 100 * time.Millisecond, TotalTicks: 3, Starts: 1, Stops: 1}
+	if got := s.Stats(); got != want {
+		t.Errorf("Stats after first run = %+v, want %+v", got, want)
+	}
+	run(2)
+	want = Stats{Ticks: 2, Elapsed: 200 * time.Millisecond, Interval: 100 * time.Millisecond, TotalTicks: 5, Starts: 2, Stops: 1}
+	if got := s.Stats(); got != want {
+		t.Errorf("Stats while running = %+v, want %+v", got, want)
+	}
+	s.Stop()
+}
@@ -0,0 +1,109 @@
+package spinner
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Style is a named frame set with the interval it is meant to run at.
+type Style struct {
+	Name     string
+	Frames   []string
+	Interval time.Duration
+}
+
+// WithStyle sets the frames of st and, if it has one, its interval.
+func WithStyle(st Style) Option {
+	return func(s *Spinner) {
+		s.frames = st.Frames
+		if d := st.Interval; d > 0 {
+			s.setSource(&s.intervalSource, &s.intervalOpt, SourceFixed, "WithStyle")
+			s.fixedInterval = d
+			s.interval = func() time.Duration { return d }
+		}
+	}
+}
+
+var (
+	stylesMu sync.RWMutex
+	styles   = map[string]Style{}
+)
+
+// RegisterStyle adds st to the style registry, replacing any style with the
+// same name. Names are matched case-insensitively.
+func RegisterStyle(st Style) {
+	stylesMu.Lock()
+	defer stylesMu.Unlock()
+	styles[strings.ToLower(st.Name)] = st
+}
+
+// LookupStyle returns the registered style with the given name.
+func LookupStyle(name string) (Style, bool) {
+	stylesMu.RLock()
+	defer stylesMu.RUnlock()
+	st, ok := styles[strings.ToLower(name)]
+	return st, ok
+}
+
+// StyleNames returns the names of all registered styles in sorted order.
+func StyleNames() []string {
+	stylesMu.RLock()
+	defer stylesMu.RUnlock()
+	names := make([]string, 0, len(styles))
+	for _, st := range styles {
+		names = append(names, st.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	for _, st := range []Style{
+		{"dots1", Dots1, 80 * time.Millisecond},
+		{"dots2", Dots2, 80 * time.Millisecond},
+		{"dots3", Dots3, 80 * time.Millisecond},
+		{"dots4", Dots4, 80 * time.Millisecond},
+		{"dots5", Dots5, 80 * time.Millisecond},
+		{"dots6", Dots6, 80 * time.Millisecond},
+		{"dots7", Dots7, 80 * time.Millisecond},
+		{"dots8", Dots8, 80 * time.Millisecond},
+		{"dots9", Dots9, 80 * time.Millisecond},
+		{"dots10", Dots10, 80 * time.Millisecond},
+		{"dots11", Dots11, 100 * time.Millisecond},
+		{"dots12", Dots12, 80 * time.Millisecond},
+		{"line", Line, 130 * time.Millisecond},
+		{"pipe", Pipe, 100 * time.Millisecond},
+		{"simpleDots", SimpleDots, 400 * time.Millisecond},
+		{"simpleDotsScrolling", SimpleDotsScrolling, 200 * time.Millisecond},
+		{"star", Star, 70 * time.Millisecond},
+		{"flip", Flip, 70 * time.Millisecond},
+		{"hamburger", Hamburger, 100 * time.Millisecond},
+		{"growVertical", GrowVertical, 120 * time.Millisecond},
+		{"growHorizontal", GrowHorizontal, 120 * time.Millisecond},
+		{"balloon", Balloon, 140 * time.Millisecond},
+		{"noise", Noise, 100 * time.Millisecond},
+		{"bounce", Bounce, 120 * time.Millisecond},
+		{"boxBounce", BoxBounce, 120 * time.Millisecond},
+		{"boxBounce2", BoxBounce2, 100 * time.Millisecond},
+		{"triangle", Triangle, 50 * time.Millisecond},
+		{"arc", Arc, 100 * time.Millisecond},
+		{"circle", Circle, 120 * time.Millisecond},
+		{"squareCorners", SquareCorners, 180 * time.Millisecond},
+		{"circleQuarters", CircleQuarters, 120 * time.Millisecond},
+		{"circleHalves", CircleHalves, 50 * time.Millisecond},
+		{"moon", Moon, 80 * time.Millisecond},
+		{"smiley", Smiley, 200 * time.Millisecond},
+		{"monkey", Monkey, 300 * time.Millisecond},
+		{"hearts", Hearts, 100 * time.Millisecond},
+		{"clock", Clock, 100 * time.Millisecond},
+		{"earth", Earth, 180 * time.Millisecond},
+		{"material", Material, 17 * time.Millisecond},
+		{"growHorizontalBar", GrowHorizontalFrames(4), 30 * time.Millisecond},
+		{"equalizer", GrowVerticalFrames(4), 80 * time.Millisecond},
+		{"tallBounce", TallBounce, 150 * time.Millisecond},
+	} {
+		RegisterStyle(st)
+	}
+}
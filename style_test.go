@@ -0,0 +1,121 @@
+package spinner_test
+
+import (
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestBuiltinStyles(t *testing.T) {
+	names := spinner.StyleNames()
+	if !slices.IsSorted(names) {
+		t.Errorf("StyleNames() not sorted: %q", names)
+	}
+	for _, name := range []string{"dots1", "dots12", "line", "simpleDotsScrolling", "material"} {
+		if !slices.Contains(names, name) {
+			t.Errorf("StyleNames() missing %q", name)
+		}
+	}
+	for _, name := range names {
+		st, ok := spinner.LookupStyle(name)
+		if !ok || st.Name != name || len(st.Frames) == 0 || st.Interval <= 0 {
+			t.Errorf("LookupStyle(%q) = %+v, %v", name, st, ok)
+		}
+	}
+	if st, ok := spinner.LookupStyle("SimpleDots"); !ok || st.Name != "simpleDots" {
+		t.Errorf("LookupStyle is not case-insensitive: %+v, %v", st, ok)
+	}
+}
+
+func TestColorNames(t *testing.T) {
+	names := spinner.ColorNames()
+	if !slices.IsSorted(names) || len(names) != len(spinner.Colors) {
+		t.Errorf("ColorNames() = %q", names)
+	}
+	if c, ok := spinner.ColorByName("Red"); !ok || c != spinner.Red {
+		t.Errorf("ColorByName(%q) = %q, %v", "Red", c, ok)
+	}
+	if c, ok := spinner.ColorByName("mauve"); ok {
+		t.Errorf("ColorByName(%q) = %q, %v", "mauve", c, ok)
+	}
+}
+
+func TestRegisterStyle(t *testing.T) {
+	spinner.RegisterStyle(spinner.Style{Name: "testArrows", Frames: []string{"←", "↑", "→", "↓"}})
+	st, ok := spinner.LookupStyle("testarrows")
+	if !ok || len(st.Frames) != 4 {
+		t.Fatalf("LookupStyle after RegisterStyle = %+v, %v", st, ok)
+	}
+	c := spinner.New(spinner.WithStyle(st)).Config()
+	if c.Frames != 4 || c.IntervalSource != spinner.SourceDefault {
+		t.Errorf("WithStyle without interval: Config() = %+v", c)
+	}
+}
+
+func TestWithStyleConflict(t *testing.T) {
+	st, _ := spinner.LookupStyle("line")
+	if _, err := spinner.NewWithError(spinner.WithStyle(st), spinner.WithInterval(time.Second)); err == nil {
+		t.Error("WithStyle and WithInterval did not conflict")
+	}
+	s, err := spinner.NewWithError(spinner.WithStyle(st))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := s.Config(); c.Interval != 130*time.Millisecond || c.Frames != len(spinner.Line) {
+		t.Errorf("WithStyle(line): Config() = %+v", c)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	var style spinner.StyleFlag
+	var color spinner.ColorFlag
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&style, "style", "")
+	fs.Var(&color, "color", "")
+	if err := fs.Parse([]string{"-style", "moon", "-color", "red"}); err != nil {
+		t.Fatal(err)
+	}
+	if style.Style.Name != "moon" || style.String() != "moon" {
+		t.Errorf("-style moon: got %+v", style)
+	}
+	if color.Color != spinner.Red || color.String() != "red" {
+		t.Errorf("-color red: got %q", color.Color)
+	}
+
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"RED", spinner.Red},
+		{"208", spinner.Color256(208)},
+		{"256:0", spinner.Color256(0)},
+		{"#ff8000", "\033[38;2;255;128;0m"},
+	} {
+		if err := color.Set(tt.in); err != nil || color.Color != tt.want {
+			t.Errorf("ColorFlag.Set(%q) = %v, Color %q, want %q", tt.in, err, color.Color, tt.want)
+		}
+	}
+	for _, in := range []string{"", "mauve", "256", "-1", "256:x", "#fff", "#gg0000"} {
+		if err := color.Set(in); err == nil {
+			t.Errorf("ColorFlag.Set(%q) succeeded", in)
+		}
+	}
+	if err := style.Set("nope"); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("StyleFlag.Set(nope) = %v", err)
+	}
+
+	var interval spinner.IntervalFlag
+	if err := interval.Set("150ms"); err != nil || interval.Interval != 150*time.Millisecond || interval.String() != "150ms" {
+		t.Errorf("IntervalFlag.Set(150ms) = %v, got %+v", err, interval)
+	}
+	for _, in := range []string{"", "fast", "0s", "-1s"} {
+		if err := interval.Set(in); err == nil {
+			t.Errorf("IntervalFlag.Set(%q) succeeded", in)
+		}
+	}
+}
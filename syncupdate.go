@@ -0,0 +1,92 @@
+package spinner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Synchronized output, DEC private mode 2026: a terminal that supports it
+// holds back drawing between the two sequences, so a repaint made of
+// several writes appears at once instead of tearing.
+const (
+	syncBegin = "\033[?2026h"
+	syncEnd   = "\033[?2026l"
+)
+
+// SyncMode says whether the spinner wraps its repaints in synchronized
+// output sequences.
+type SyncMode int
+
+const (
+	// SyncAuto synchronizes repaints on terminals known to support it, going
+	// by TERM, TERM_PROGRAM and KITTY_WINDOW_ID, and never for a writer that
+	// is not a terminal. It is the default.
+	SyncAuto SyncMode = iota
+	// SyncAlways synchronizes repaints everywhere. Terminals without
+	// support ignore the sequences.
+	SyncAlways
+	// SyncNever writes repaints as they are.
+	SyncNever
+)
+
+func (m SyncMode) String() string {
+	switch m {
+	case SyncAuto:
+		return "auto"
+	case SyncAlways:
+		return "always"
+	case SyncNever:
+		return "never"
+	}
+	return fmt.Sprintf("SyncMode(%d)", int(m))
+}
+
+// WithSyncUpdates sets whether repaints that could tear, such as a frame of
+// several lines, a line printed above the spinner or the final line, are
+// wrapped in the synchronized output sequences of kitty, iTerm2, WezTerm,
+// foot and others. A frame of several lines carries the sequences in its
+// single write. A single-line frame is one short write and is never
+// wrapped. The default is SyncAuto.
+func WithSyncUpdates(mode SyncMode) Option {
+	return func(s *Spinner) {
+		s.syncMode = mode
+	}
+}
+
+// syncSupported reports whether the environment names a terminal that
+// supports synchronized output.
+func syncSupported() bool {
+	if os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "ghostty":
+		return true
+	}
+	term := os.Getenv("TERM")
+	for _, prefix := range []string{"xterm-kitty", "foot", "wezterm", "xterm-ghostty", "contour"} {
+		if strings.HasPrefix(term, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// beginSync starts a synchronized repaint of several writes if the spinner
+// uses them. The caller must hold writerLock until the matching endSync.
+func (s *Spinner) beginSync() {
+	if s.syncUpdates {
+		io.WriteString(s.writer, syncBegin)
+		s.inSync = true
+	}
+}
+
+// endSync ends a repaint started by beginSync.
+func (s *Spinner) endSync() {
+	if s.syncUpdates {
+		io.WriteString(s.writer, syncEnd)
+		s.inSync = false
+	}
+}
@@ -0,0 +1,54 @@
+package spinner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncUpdates(t *testing.T) {
+	run := func(frames []string, opts ...Option) string {
+		var buf bytes.Buffer
+		opts = append([]Option{WithWriter(&buf), WithFrames(frames), WithColorMode(ColorNever)}, opts...)
+		script(opts, every(time.Millisecond, 2), func(s *Spinner) {
+			s.Println("log")
+			s.Stop()
+		})
+		return buf.String()
+	}
+	single := []string{"a", "b"}
+
+	t.Setenv("TERM", "xterm")
+	out := run(single, WithSyncUpdates(SyncAlways))
+	for _, want := range []string{"\ra", "\rb", syncBegin + "\r \rlog\nb" + syncEnd} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q does not contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, syncBegin+"\ra") || strings.Contains(out, syncBegin+"\rb") {
+		t.Errorf("output %q synchronizes a single-line frame", out)
+	}
+	out = run([]string{"a", "b\nc"}, WithSyncUpdates(SyncAlways))
+	if want := syncBegin + "\033[1A\r\033[2Kb\n\r\033[2Kc" + syncEnd; !strings.Contains(out, want) {
+		t.Errorf("output %q does not contain %q", out, want)
+	}
+
+	t.Setenv("TERM", "xterm-kitty")
+	outputs := map[string]string{
+		"never":              run(single, WithSyncUpdates(SyncNever), WithForceTTY(true)),
+		"auto, non-terminal": run(single, WithForceTTY(false)),
+	}
+	t.Setenv("TERM", "xterm")
+	outputs["auto, unsupported"] = run(single, WithForceTTY(true))
+	for name, out := range outputs {
+		if strings.Contains(out, syncBegin) {
+			t.Errorf("%s: output %q synchronizes repaints", name, out)
+		}
+	}
+
+	t.Setenv("TERM", "xterm-kitty")
+	if out := run(single, WithForceTTY(true)); !strings.Contains(out, syncBegin+"\r \rlog") {
+		t.Errorf("auto on kitty: output %q does not synchronize repaints", out)
+	}
+}
@@ -0,0 +1,147 @@
+package spinner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// fder is a writer that is not an *os.File but wraps one, such as a
+// colorable writer, and exposes its file descriptor.
+type fder interface {
+	Fd() uintptr
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+// Besides an *os.File, w may be a writer with an Fd method; any other
+// writer, such as a bytes.Buffer, is not a terminal.
+func isTerminal(w io.Writer) bool {
+	switch f := w.(type) {
+	case *os.File:
+		fi, err := f.Stat()
+		if err != nil {
+			return false
+		}
+		return fi.Mode()&os.ModeCharDevice != 0
+	case fder:
+		return fdIsTerminal(f.Fd())
+	}
+	return false
+}
+
+// WithForceTTY makes the spinner treat its writer as a terminal whether or
+// not it can tell, for writers that wrap a terminal without exposing it,
+// either as an *os.File or through an Fd method.
+func WithForceTTY(force bool) Option {
+	return func(s *Spinner) {
+		s.forceTTY = force
+	}
+}
+
+// terminalWidth returns the width in columns of the terminal w writes to,
+// falling back to the COLUMNS variable, or zero if it is unknown.
+func terminalWidth(w io.Writer) int {
+	if isTerminal(w) {
+		var n int
+		switch f := w.(type) {
+		case *os.File:
+			n = winsizeColumns(f)
+		case fder:
+			n = fdColumns(f.Fd())
+		}
+		if n > 0 {
+			return n
+		}
+	}
+	n, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// isDumbTerminal reports whether TERM names a terminal without support for
+// escape sequences.
+func isDumbTerminal() bool {
+	return os.Getenv("TERM") == "dumb"
+}
+
+// Term writes cursor and line control sequences to a writer, or nothing at
+// all when the writer cannot interpret them.
+type Term struct {
+	w    io.Writer
+	ansi bool
+	dumb bool // TERM is "dumb"
+}
+
+// NewTerm returns a Term for w. Sequences are written only if w is a
+// terminal and TERM is not "dumb".
+func NewTerm(w io.Writer) *Term {
+	return newTerm(w, isTerminal(w))
+}
+
+// newTerm is NewTerm for a writer that is a terminal if tty is set.
+func newTerm(w io.Writer, tty bool) *Term {
+	dumb := isDumbTerminal()
+	return &Term{w: w, ansi: tty && !dumb, dumb: dumb}
+}
+
+// ANSI reports whether t writes escape sequences.
+func (t *Term) ANSI() bool {
+	return t.ansi
+}
+
+func (t *Term) write(seq string) error {
+	if !t.ansi || seq == "" {
+		return nil
+	}
+	_, err := io.WriteString(t.w, seq)
+	return err
+}
+
+// clearSeq returns the sequence ClearLine writes, or "" if t writes none.
+func (t *Term) clearSeq() string {
+	if !t.ansi {
+		return ""
+	}
+	return "\r" + EraseLine
+}
+
+// upSeq returns the sequence CursorUp writes, or "" if t writes none.
+func (t *Term) upSeq(n int) string {
+	if !t.ansi || n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("\033[%dA", n)
+}
+
+// ClearLine erases the current line and moves the cursor to its start.
+func (t *Term) ClearLine() error {
+	return t.write(t.clearSeq())
+}
+
+// CursorUp moves the cursor up n lines.
+func (t *Term) CursorUp(n int) error {
+	return t.write(t.upSeq(n))
+}
+
+// HideCursor hides the cursor.
+func (t *Term) HideCursor() error {
+	return t.write(HideCursor)
+}
+
+// ShowCursor shows the cursor again.
+func (t *Term) ShowCursor() error {
+	return t.write(ShowCursor)
+}
+
+// Term returns the Term the spinner draws with. It follows NewTerm's rules,
+// with WithForceTTY deciding whether the writer is a terminal, and does not
+// coordinate with the spinner's own drawing, so it is best used while the
+// spinner is stopped.
+func (s *Spinner) Term() *Term {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.term
+}
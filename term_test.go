@@ -0,0 +1,98 @@
+package spinner_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/tmc/spinner"
+)
+
+func TestTerm(t *testing.T) {
+	draw := func(term *spinner.Term) {
+		term.HideCursor()
+		term.CursorUp(2)
+		term.CursorUp(0)
+		term.ClearLine()
+		term.ShowCursor()
+	}
+
+	var buf bytes.Buffer
+	t.Setenv("TERM", "xterm")
+	draw(spinner.New(spinner.WithWriter(&buf), spinner.WithForceTTY(true)).Term())
+	if got, want := buf.String(), "\033[?25l\033[2A\r\033[2K\033[?25h"; got != want {
+		t.Errorf("spinner Term wrote %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	draw(spinner.NewTerm(&buf))
+	if buf.Len() != 0 {
+		t.Errorf("Term for a non-terminal wrote %q", buf.String())
+	}
+	draw(spinner.New(spinner.WithWriter(&buf)).Term())
+	if buf.Len() != 0 {
+		t.Errorf("spinner Term for a non-terminal wrote %q", buf.String())
+	}
+
+	t.Setenv("TERM", "dumb")
+	draw(spinner.New(spinner.WithWriter(&buf), spinner.WithForceTTY(true)).Term())
+	if buf.Len() != 0 {
+		t.Errorf("Term on a dumb terminal wrote %q", buf.String())
+	}
+}
+
+// fdWriter wraps a file as some writers, such as colorable ones, do,
+// exposing its descriptor but not the *os.File itself.
+type fdWriter struct {
+	io.Writer
+	fd uintptr
+}
+
+func (w fdWriter) Fd() uintptr { return w.fd }
+
+func TestWriterWithoutFile(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	for _, key := range []string{"NO_COLOR", "CLICOLOR_FORCE", "FORCE_COLOR"} {
+		setenv(t, key, nil)
+	}
+
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithOSCProgress(true))
+	if c := s.Config(); c.TTY || c.ColorMode != spinner.ColorNever {
+		t.Errorf("bytes.Buffer: TTY %v, color mode %v, want a non-terminal", c.TTY, c.ColorMode)
+	}
+	s.SetProgress(1, 2)
+	s.Start()
+	s.Stop()
+
+	s = spinner.New(spinner.WithWriter(&buf), spinner.WithForceTTY(true))
+	if c := s.Config(); !c.TTY || c.ColorMode != spinner.ColorAlways {
+		t.Errorf("WithForceTTY: TTY %v, color mode %v, want a terminal", c.TTY, c.ColorMode)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if spinner.New(spinner.WithWriter(fdWriter{w, w.Fd()})).Config().TTY {
+		t.Error("writer wrapping a pipe taken for a terminal")
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	// A wrapper is detected as the file it wraps would be.
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer null.Close()
+	want := spinner.New(spinner.WithWriter(null)).Config().TTY
+	if got := spinner.New(spinner.WithWriter(fdWriter{null, null.Fd()})).Config().TTY; got != want {
+		t.Errorf("writer wrapping %s: TTY %v, want %v as for the file", os.DevNull, got, want)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package spinner
+
+import "syscall"
+
+const (
+	ioctlGetTermios      = syscall.TIOCGETA
+	ioctlSetTermios      = syscall.TIOCSETA
+	ioctlFlushSetTermios = syscall.TIOCSETAF
+)
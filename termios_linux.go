@@ -0,0 +1,9 @@
+package spinner
+
+import "syscall"
+
+const (
+	ioctlGetTermios      = syscall.TCGETS
+	ioctlSetTermios      = syscall.TCSETS
+	ioctlFlushSetTermios = syscall.TCSETS + 2 // TCSETSF on every architecture
+)
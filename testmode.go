@@ -0,0 +1,44 @@
+package spinner
+
+import "fmt"
+
+// WithTestMode makes the spinner write a plain line, with no escape
+// sequences or carriage returns, for each step instead of animating on a
+// timer: one when it starts and one for each call to UpdateMessage or
+// SetProgress, each showing the next frame. Stop writes nothing and the
+// finishers write their final line as usual. The output depends only on
+// the calls made, so examples and tests can compare it exactly, and it
+// shows the order in which frames are drawn. Since each step is written
+// before the call returns, UpdateMessage takes the spinner's lock in test
+// mode, as SetProgress always does.
+func WithTestMode(enable bool) Option {
+	return func(s *Spinner) {
+		s.testMode.Store(enable)
+	}
+}
+
+// testStep writes the next test mode line if the spinner is running.
+func (s *Spinner) testStep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.plain || s.state != stateRunning {
+		return
+	}
+	s.writerLock.Lock()
+	defer s.writerLock.Unlock()
+	s.testFrame()
+}
+
+// testFrame writes the current line, frame included, on a line of its own
+// and moves on to the next frame.
+func (s *Spinner) testFrame() {
+	s.applyMessage()
+	line, _ := s.render(true)
+	fmt.Fprintln(s.writer, line)
+	if n := len(s.frames); n > 0 {
+		s.index = (s.index + 1) % n
+	}
+	s.ticks++
+	s.totalTicks++
+	s.publish()
+}
@@ -0,0 +1,362 @@
+package spinner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Theme bundles the look of a spinner, so that a set of programs can
+// share one by applying it with WithTheme or loading it with LoadTheme.
+// Colors are escape sequences, such as Red or the result of RGB. Empty
+// fields, and a Style without frames, leave the spinner's own setting in
+// place.
+type Theme struct {
+	Name  string
+	Style Style
+
+	Color        string // the frames
+	MessageColor string
+	SuccessColor string
+	FailColor    string
+	WarnColor    string
+
+	SuccessSymbol string
+	FailSymbol    string
+	WarnSymbol    string
+	StopSymbol    string // see WithStopSymbol
+
+	Prefix    string
+	Suffix    string
+	Separator string
+}
+
+// WithTheme applies the non-empty settings of t.
+func WithTheme(t Theme) Option {
+	return func(s *Spinner) {
+		if len(t.Style.Frames) > 0 {
+			WithStyle(t.Style)(s)
+		}
+		if t.Color != "" {
+			WithColor(t.Color)(s)
+		}
+		set := func(field *string, v string) {
+			if v != "" {
+				*field = v
+			}
+		}
+		set(&s.messageColor, t.MessageColor)
+		set(&s.successColor, t.SuccessColor)
+		set(&s.failColor, t.FailColor)
+		set(&s.warnColor, t.WarnColor)
+		set(&s.successSymbol, t.SuccessSymbol)
+		set(&s.failSymbol, t.FailSymbol)
+		set(&s.warnSymbol, t.WarnSymbol)
+		set(&s.stopSymbol, t.StopSymbol)
+		set(&s.prefix, t.Prefix)
+		set(&s.suffix, t.Suffix)
+		set(&s.separator, t.Separator)
+	}
+}
+
+// LoadTheme reads a theme in the JSON form of Theme.UnmarshalJSON from r.
+func LoadTheme(r io.Reader) (Theme, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Theme{}, err
+	}
+	var t Theme
+	if err := t.UnmarshalJSON(data); err != nil {
+		return Theme{}, err
+	}
+	return t, nil
+}
+
+// A ThemeError reports an invalid theme and where in its JSON the problem
+// is. Line and Column count from 1 and point into the JSON given to
+// LoadTheme or Theme.UnmarshalJSON.
+type ThemeError struct {
+	Field        string // the offending field, such as "style.interval", or empty for bad JSON
+	Line, Column int
+	Err          error
+}
+
+func (e *ThemeError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("spinner: theme %d:%d: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("spinner: theme %d:%d: %s: %v", e.Line, e.Column, e.Field, e.Err)
+}
+
+func (e *ThemeError) Unwrap() error { return e.Err }
+
+type themeJSON struct {
+	Name          string     `json:"name,omitempty"`
+	Style         *styleJSON `json:"style,omitempty"`
+	Color         string     `json:"color,omitempty"`
+	MessageColor  string     `json:"messageColor,omitempty"`
+	SuccessColor  string     `json:"successColor,omitempty"`
+	FailColor     string     `json:"failColor,omitempty"`
+	WarnColor     string     `json:"warnColor,omitempty"`
+	SuccessSymbol string     `json:"successSymbol,omitempty"`
+	FailSymbol    string     `json:"failSymbol,omitempty"`
+	WarnSymbol    string     `json:"warnSymbol,omitempty"`
+	StopSymbol    string     `json:"stopSymbol,omitempty"`
+	Prefix        string     `json:"prefix,omitempty"`
+	Suffix        string     `json:"suffix,omitempty"`
+	Separator     string     `json:"separator,omitempty"`
+}
+
+type styleJSON struct {
+	Name     string   `json:"name,omitempty"`
+	Frames   []string `json:"frames,omitempty"`
+	Interval string   `json:"interval,omitempty"`
+}
+
+// MarshalJSON encodes t as an object with the fields of Theme in lower
+// camel case, leaving out empty ones. Colors are written as a name from
+// Colors, a 256-color number or #rrggbb where possible, and durations in
+// the form of time.Duration.String.
+func (t Theme) MarshalJSON() ([]byte, error) {
+	j := themeJSON{
+		Name:          t.Name,
+		Color:         colorSpec(t.Color),
+		MessageColor:  colorSpec(t.MessageColor),
+		SuccessColor:  colorSpec(t.SuccessColor),
+		FailColor:     colorSpec(t.FailColor),
+		WarnColor:     colorSpec(t.WarnColor),
+		SuccessSymbol: t.SuccessSymbol,
+		FailSymbol:    t.FailSymbol,
+		WarnSymbol:    t.WarnSymbol,
+		StopSymbol:    t.StopSymbol,
+		Prefix:        t.Prefix,
+		Suffix:        t.Suffix,
+		Separator:     t.Separator,
+	}
+	if st := t.Style; st.Name != "" || len(st.Frames) > 0 || st.Interval != 0 {
+		j.Style = &styleJSON{Name: st.Name, Frames: st.Frames}
+		if st.Interval != 0 {
+			j.Style.Interval = st.Interval.String()
+		}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a theme written by MarshalJSON. Colors may also be
+// given as "256:n" or as a raw escape sequence. A style with a name but no
+// frames is looked up with LookupStyle. Fields it does not know are
+// ignored, so that themes written for later versions still load; invalid
+// values are reported as a *ThemeError.
+func (t *Theme) UnmarshalJSON(data []byte) error {
+	var th Theme
+	err := decodeObject(data, 0, func(key string, value []byte, off int64) error {
+		str := func(field *string) error {
+			return json.Unmarshal(value, field)
+		}
+		color := func(field *string) error {
+			var v string
+			if err := json.Unmarshal(value, &v); err != nil {
+				return err
+			}
+			c, err := parseThemeColor(v)
+			*field = c
+			return err
+		}
+		switch key {
+		case "name":
+			return str(&th.Name)
+		case "style":
+			return decodeStyle(value, off, &th.Style)
+		case "color":
+			return color(&th.Color)
+		case "messageColor":
+			return color(&th.MessageColor)
+		case "successColor":
+			return color(&th.SuccessColor)
+		case "failColor":
+			return color(&th.FailColor)
+		case "warnColor":
+			return color(&th.WarnColor)
+		case "successSymbol":
+			return str(&th.SuccessSymbol)
+		case "failSymbol":
+			return str(&th.FailSymbol)
+		case "warnSymbol":
+			return str(&th.WarnSymbol)
+		case "stopSymbol":
+			return str(&th.StopSymbol)
+		case "prefix":
+			return str(&th.Prefix)
+		case "suffix":
+			return str(&th.Suffix)
+		case "separator":
+			return str(&th.Separator)
+		}
+		return nil
+	})
+	if err != nil {
+		return positioned(data, err)
+	}
+	*t = th
+	return nil
+}
+
+// decodeStyle decodes the style object in data, found at offset off of the
+// theme, into st.
+func decodeStyle(data []byte, off int64, st *Style) error {
+	err := decodeObject(data, off, func(key string, value []byte, off int64) error {
+		switch key {
+		case "name":
+			return json.Unmarshal(value, &st.Name)
+		case "frames":
+			if err := json.Unmarshal(value, &st.Frames); err != nil {
+				return err
+			}
+			for _, p := range ValidateFrames(st.Frames) {
+				if p.Severity == SeverityError {
+					return errors.New(p.String())
+				}
+			}
+		case "interval":
+			var v string
+			if err := json.Unmarshal(value, &v); err != nil {
+				return err
+			}
+			d, err := time.ParseDuration(v)
+			if err == nil && d <= 0 {
+				err = fmt.Errorf("interval %s is not positive", v)
+			}
+			st.Interval = d
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		var fe *fieldError
+		if errors.As(err, &fe) && fe.field != "" {
+			fe.field = "style." + fe.field
+		}
+		return err
+	}
+	if len(st.Frames) == 0 && st.Name != "" {
+		registered, ok := LookupStyle(st.Name)
+		if !ok {
+			return fmt.Errorf("unknown style %q", st.Name)
+		}
+		if st.Interval == 0 {
+			st.Interval = registered.Interval
+		}
+		st.Frames = registered.Frames
+	}
+	return nil
+}
+
+// fieldError is an error in the value of field at offset off of a theme's
+// JSON.
+type fieldError struct {
+	field string
+	off   int64
+	err   error
+}
+
+func (e *fieldError) Error() string { return e.err.Error() }
+
+// decodeObject calls field for each member of the JSON object in data, with
+// the offset of its value. base is the offset of data in the whole theme.
+// Errors from field are returned as a *fieldError.
+func decodeObject(data []byte, base int64, field func(key string, value []byte, off int64) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	syntax := func(err error) error {
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			// Offset counts the offending byte.
+			return &fieldError{off: base + se.Offset - 1, err: err}
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return &fieldError{off: base + dec.InputOffset(), err: err}
+	}
+	if tok, err := dec.Token(); err != nil {
+		return syntax(err)
+	} else if tok != json.Delim('{') {
+		return &fieldError{off: base, err: errors.New("not a JSON object")}
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return syntax(err)
+		}
+		key := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return syntax(err)
+		}
+		off := base + dec.InputOffset() - int64(len(value))
+		if err := field(key, value, off); err != nil {
+			var fe *fieldError
+			if !errors.As(err, &fe) {
+				return &fieldError{field: key, off: off, err: err}
+			}
+			if fe.field == "" {
+				fe.field = key
+			}
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return syntax(err)
+	}
+	return nil
+}
+
+// positioned turns a *fieldError from decoding data into a *ThemeError.
+func positioned(data []byte, err error) error {
+	var fe *fieldError
+	if !errors.As(err, &fe) {
+		return err
+	}
+	before := data[:min(max(fe.off, 0), int64(len(data)))]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return &ThemeError{Field: fe.field, Line: line, Column: col, Err: fe.err}
+}
+
+// parseThemeColor parses a color as ColorFlag does, also accepting a raw
+// escape sequence.
+func parseThemeColor(v string) (string, error) {
+	if v == "" || strings.HasPrefix(v, "\033") {
+		return v, nil
+	}
+	return parseColor(v)
+}
+
+// colorSpec returns the form of color that parseThemeColor reads back: its
+// name in Colors, its number in the 256-color palette, #rrggbb, or the
+// escape sequence itself.
+func colorSpec(color string) string {
+	for name, c := range Colors {
+		if c == color {
+			return name
+		}
+	}
+	if n, ok := strings.CutPrefix(color, "\033[38;5;"); ok {
+		if n, ok := strings.CutSuffix(n, "m"); ok {
+			if v, err := strconv.Atoi(n); err == nil && Color256(v) == color {
+				return n
+			}
+		}
+	}
+	if rgb, ok := strings.CutPrefix(color, "\033[38;2;"); ok {
+		var r, g, b int
+		if n, _ := fmt.Sscanf(rgb, "%d;%d;%dm", &r, &g, &b); n == 3 && RGB(r, g, b) == color {
+			return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+		}
+	}
+	return color
+}
@@ -0,0 +1,111 @@
+package spinner
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThemeRoundTrip(t *testing.T) {
+	for _, th := range []Theme{
+		{},
+		{Name: "plain", Style: Style{Name: "ascii", Frames: []string{"-", "\\", "|", "/"}, Interval: 90 * time.Millisecond}},
+		{
+			Name:          "corp",
+			Style:         Style{Frames: []string{"a", "b"}},
+			Color:         Red,
+			MessageColor:  Color256(200),
+			SuccessColor:  RGB(1, 2, 255),
+			FailColor:     "\033[1;31m",
+			WarnColor:     Yellow,
+			SuccessSymbol: "OK",
+			FailSymbol:    "NO",
+			WarnSymbol:    "!!",
+			StopSymbol:    "--",
+			Prefix:        "[",
+			Suffix:        "]",
+			Separator:     " | ",
+		},
+	} {
+		data, err := th.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON(%+v): %v", th, err)
+		}
+		got, err := LoadTheme(strings.NewReader(string(data)))
+		if err != nil {
+			t.Fatalf("LoadTheme(%s): %v", data, err)
+		}
+		if !reflect.DeepEqual(got, th) {
+			t.Errorf("round trip through %s:\ngot  %+v\nwant %+v", data, got, th)
+		}
+	}
+}
+
+func TestThemeJSON(t *testing.T) {
+	th := Theme{Color: Red, SuccessColor: Color256(200), FailColor: RGB(255, 0, 16), Style: Style{Interval: time.Second}}
+	data, err := th.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"style":{"interval":"1s"},"color":"red","successColor":"200","failColor":"#ff0010"}`
+	if string(data) != want {
+		t.Errorf("MarshalJSON = %s, want %s", data, want)
+	}
+
+	got, err := LoadTheme(strings.NewReader(`{"style": {"name": "line"}, "color": "256:9", "future": {"x": [1]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Style.Interval != 130*time.Millisecond || !reflect.DeepEqual(got.Style.Frames, Line) || got.Color != Red {
+		t.Errorf("LoadTheme = %+v", got)
+	}
+}
+
+func TestThemeErrors(t *testing.T) {
+	for _, tt := range []struct {
+		json         string
+		field        string
+		line, column int
+	}{
+		{"{\n  \"color\": \"mauve\"\n}", "color", 2, 12},
+		{"{\"prefix\": 3}", "prefix", 1, 12},
+		{"{\n\"style\": {\n  \"interval\": \"-1s\"}}", "style.interval", 3, 15},
+		{"{\n\"style\": {\"frames\": []}}", "style.frames", 2, 21},
+		{"{\"style\": {\"name\": \"nope\"}}", "style", 1, 11},
+		{"{\"name\": \"x\",\n \"color\" \"red\"}", "", 2, 10},
+		{"[]", "", 1, 1},
+	} {
+		_, err := LoadTheme(strings.NewReader(tt.json))
+		var te *ThemeError
+		if !errors.As(err, &te) {
+			t.Errorf("LoadTheme(%q) = %v, want a *ThemeError", tt.json, err)
+			continue
+		}
+		if te.Field != tt.field || te.Line != tt.line || te.Column != tt.column {
+			t.Errorf("LoadTheme(%q) = %v, want %s at %d:%d", tt.json, err, tt.field, tt.line, tt.column)
+		}
+	}
+}
+
+func TestWithTheme(t *testing.T) {
+	th := Theme{
+		Style:         Style{Frames: []string{"x"}},
+		Color:         Blue,
+		MessageColor:  Grey,
+		SuccessColor:  Aqua,
+		SuccessSymbol: "OK",
+		Prefix:        ">",
+	}
+	out := script([]Option{WithColorMode(ColorAlways), WithMessage("work"), WithTheme(th)}, every(time.Second, 1), func(s *Spinner) {
+		s.Success("done")
+	})
+	want := ">" + " " + Blue + "x" + Reset + " " + Grey + "work" + Reset
+	if !strings.Contains(string(out), want) {
+		t.Errorf("output %q does not contain %q", out, want)
+	}
+	if want := Aqua + "OK" + Reset + " done\n"; !strings.Contains(string(out), want) {
+		t.Errorf("output %q does not contain %q", out, want)
+	}
+}
@@ -0,0 +1,74 @@
+package spinner
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// titleEvery is the least time between two terminal title updates.
+const titleEvery = 250 * time.Millisecond
+
+// WithTerminalTitle mirrors the spinner's status into the terminal's window
+// or tab title with OSC 2, so that it can be followed while the window is in
+// the background. The title is format, such as "build: %s", formatted with
+// the message followed by the percentage once progress is known. It is
+// updated when it changes, at most four times a second, and on Stop the
+// title the terminal had before is restored, or the one given with
+// WithTerminalTitleRestore. Nothing is sent unless the writer is a terminal
+// that supports titles.
+func WithTerminalTitle(format string) Option {
+	return func(s *Spinner) {
+		s.titleFormat = format
+	}
+}
+
+// WithTerminalTitleRestore sets the title WithTerminalTitle leaves on Stop,
+// for terminals that cannot restore the one they had before.
+func WithTerminalTitleRestore(title string) Option {
+	return func(s *Spinner) {
+		s.titleRestore = title
+	}
+}
+
+// titleSupported reports whether the terminal is one that shows OSC 2
+// titles: the Linux console does not and prints them instead.
+func titleSupported() bool {
+	return os.Getenv("TERM") != "linux"
+}
+
+// writeTitle sends the terminal title for the current message and progress
+// if it changed and the last update was long enough ago. The terminal's own
+// title is saved on its title stack before the first update.
+func (s *Spinner) writeTitle(now time.Time) {
+	if s.titleFormat == "" || !s.tty || s.plain || !titleSupported() {
+		return
+	}
+	status := s.message
+	if s.total > 0 {
+		status = fmt.Sprintf("%s %d%%", status, percent(s.current, s.total))
+	}
+	title := sanitizeMessage(fmt.Sprintf(s.titleFormat, status), " ")
+	if title == s.title || !s.titleAt.IsZero() && now.Sub(s.titleAt) < titleEvery {
+		return
+	}
+	if !s.titleSaved {
+		fmt.Fprint(s.writer, "\033[22;2t")
+		s.titleSaved = true
+	}
+	fmt.Fprintf(s.writer, "\033]2;%s\a", title)
+	s.title, s.titleAt = title, now
+}
+
+// restoreTitle puts back the title the terminal had before the first
+// update, or the configured one.
+func (s *Spinner) restoreTitle() {
+	if !s.titleSaved {
+		return
+	}
+	fmt.Fprint(s.writer, "\033[23;2t")
+	if s.titleRestore != "" {
+		fmt.Fprintf(s.writer, "\033]2;%s\a", sanitizeMessage(s.titleRestore, " "))
+	}
+	s.title, s.titleAt, s.titleSaved = "", time.Time{}, false
+}
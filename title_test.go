@@ -0,0 +1,60 @@
+package spinner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTerminalTitle(t *testing.T) {
+	run := func(tty bool, opts ...Option) string {
+		var buf bytes.Buffer
+		clock := &fakeClock{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		s := New(append([]Option{WithWriter(&buf), WithMessage("compiling")}, opts...)...)
+		s.tty = tty
+		s.now = clock.now
+		s.loop = manualLoop
+		s.Start()
+		for i := range 6 {
+			clock.t = clock.t.Add(100 * time.Millisecond)
+			if i == 1 {
+				s.SetProgress(1, 4)
+			}
+			s.tick()
+		}
+		s.Stop()
+		return strings.TrimSuffix(buf.String(), "\033[?25h")
+	}
+
+	t.Setenv("TERM", "xterm")
+	out := run(true, WithTerminalTitle("build: %s"))
+	// The progress set at 200ms waits for the rate limit to allow it at 400ms.
+	want := "\033[22;2t\033]2;build: compiling\a"
+	if !strings.Contains(out, want) || strings.Count(out, "\033]2;") != 2 {
+		t.Errorf("output %q does not set the title twice, starting with %q", out, want)
+	}
+	if !strings.Contains(out, "\033]2;build: compiling 25%\a") {
+		t.Errorf("output %q does not show progress in the title", out)
+	}
+	if !strings.HasSuffix(out, "\033[23;2t") {
+		t.Errorf("output %q does not end by restoring the title", out)
+	}
+
+	out = run(true, WithTerminalTitle("build: %s"), WithTerminalTitleRestore("shell"))
+	if !strings.HasSuffix(out, "\033[23;2t\033]2;shell\a") {
+		t.Errorf("output %q does not end by setting the configured title", out)
+	}
+
+	outputs := map[string]string{
+		"not a terminal": run(false, WithTerminalTitle("build: %s")),
+		"disabled":       run(true),
+	}
+	t.Setenv("TERM", "linux")
+	outputs["linux console"] = run(true, WithTerminalTitle("build: %s"))
+	for name, out := range outputs {
+		if strings.Contains(out, "\033]2;") || strings.Contains(out, "\033[22;2t") {
+			t.Errorf("%s: output %q sets the title", name, out)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package spinner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Truncation says which part of a message is cut when it is too long.
+type Truncation int
+
+const (
+	TruncateTail   Truncation = iota // keep the start: "a long messa…"
+	TruncateHead                     // keep the end: "…long message"
+	TruncateMiddle                   // keep both ends: "a lon…essage"
+)
+
+func (t Truncation) String() string {
+	switch t {
+	case TruncateTail:
+		return "tail"
+	case TruncateHead:
+		return "head"
+	case TruncateMiddle:
+		return "middle"
+	}
+	return fmt.Sprintf("Truncation(%d)", int(t))
+}
+
+// WithMaxLineLength caps the spinner's line at n columns, whatever the
+// width of the terminal, by truncating the message as set by WithTruncate.
+// Zero, the default, means no limit.
+func WithMaxLineLength(n int) Option {
+	return func(s *Spinner) {
+		s.maxLine = n
+	}
+}
+
+// WithTruncate sets how the message is cut to fit WithMaxLineLength. The
+// default is TruncateTail; TruncateMiddle suits file paths.
+func WithTruncate(t Truncation) Option {
+	return func(s *Spinner) {
+		s.truncation = t
+	}
+}
+
+// Truncate shortens text to at most width terminal columns, marking the cut
+// with "…". Wide characters count as two columns. Escape sequences take no
+// columns and are always kept, so colors are still reset, and characters
+// are never separated from the combining marks or joiners that follow them.
+// Text that already fits is returned unchanged.
+func Truncate(text string, width int, t Truncation) string {
+	return truncateTokens(text, tokenize(text, defaultEmojiWidth), width, t)
+}
+
+// truncate is Truncate with clusters measured by the spinner's width func.
+func (s *Spinner) truncate(text string, width int, t Truncation) string {
+	toks := tokenize(text, s.emojiWidth)
+	if s.widthFunc != nil {
+		for i := range toks {
+			if !toks[i].escape {
+				toks[i].width = s.widthFunc(toks[i].text)
+			}
+		}
+	}
+	return truncateTokens(text, toks, width, t)
+}
+
+// truncateTokens is Truncate for text already split into toks.
+func truncateTokens(text string, toks []token, width int, t Truncation) string {
+	total := 0
+	for _, tok := range toks {
+		total += tok.width
+	}
+	if total <= width {
+		return text
+	}
+
+	keep := make([]bool, len(toks))
+	avail := width - 1 // room left after the ellipsis
+	fill := func(i, step, budget int) {
+		for ; i >= 0 && i < len(toks) && toks[i].width <= budget; i += step {
+			if !toks[i].escape {
+				budget -= toks[i].width
+				keep[i] = true
+			}
+		}
+	}
+	switch t {
+	case TruncateHead:
+		fill(len(toks)-1, -1, avail)
+	case TruncateMiddle:
+		fill(0, 1, avail-avail/2)
+		fill(len(toks)-1, -1, avail/2)
+	default:
+		fill(0, 1, avail)
+	}
+
+	var b strings.Builder
+	ellipsis := width < 1
+	for i, tok := range toks {
+		switch {
+		case tok.escape || keep[i]:
+			b.WriteString(tok.text)
+		case !ellipsis:
+			b.WriteString("…")
+			ellipsis = true
+		}
+	}
+	return b.String()
+}
+
+// displayWidth is stringWidth for text that may contain escape sequences.
+func displayWidth(text string) int {
+	return displayWidthEmoji(text, defaultEmojiWidth)
+}
+
+// displayWidthEmoji is stringWidthEmoji for text that may contain escape
+// sequences.
+func displayWidthEmoji(text string, emoji int) int {
+	w := 0
+	for _, tok := range tokenize(text, emoji) {
+		w += tok.width
+	}
+	return w
+}
+
+// stripEscapes returns text without its escape sequences.
+func stripEscapes(text string) string {
+	if !strings.ContainsAny(text, "\033\u009b\u009d") {
+		return text
+	}
+	var b strings.Builder
+	for _, tok := range tokenize(text, defaultEmojiWidth) {
+		if !tok.escape {
+			b.WriteString(tok.text)
+		}
+	}
+	return b.String()
+}
+
+type token struct {
+	text   string
+	width  int
+	escape bool
+}
+
+// tokenize splits text into escape sequences and clusters: a character
+// together with the combining marks, variation selectors and zero-width
+// joined characters that follow it, or a pair of regional indicators. A
+// narrow character turned into an emoji by U+FE0F is emoji columns wide.
+func tokenize(text string, emoji int) []token {
+	var toks []token
+	for i := 0; i < len(text); {
+		r, n := utf8.DecodeRuneInString(text[i:])
+		if r == '\033' || r == 0x9b || r == 0x9d {
+			n = escapeLen(text[i:])
+			toks = append(toks, token{text[i : i+n], 0, true})
+			i += n
+			continue
+		}
+		j := i + n
+		w := runeWidth(r)
+	cluster:
+		for j < len(text) {
+			next, m := utf8.DecodeRuneInString(text[j:])
+			switch {
+			case next == '\u200d':
+				// A joiner takes the following character with it.
+				j += m
+				if j < len(text) {
+					_, m = utf8.DecodeRuneInString(text[j:])
+					j += m
+				}
+			case unicode.In(next, unicode.Mn, unicode.Me) || next >= 0xfe00 && next <= 0xfe0f:
+				if next == '\ufe0f' && w == 1 {
+					w = emoji
+				}
+				j += m
+			case isRegionalIndicator(r) && isRegionalIndicator(next) && j == i+n:
+				// A flag, drawn two columns wide.
+				j += m
+				w = 2
+			default:
+				break cluster
+			}
+		}
+		toks = append(toks, token{text[i:j], w, false})
+		i = j
+	}
+	return toks
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,58 @@
+package spinner_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		mode  spinner.Truncation
+		want  string
+	}{
+		{"short", 10, spinner.TruncateTail, "short"},
+		{"exactly10!", 10, spinner.TruncateTail, "exactly10!"},
+		{"a long message", 8, spinner.TruncateTail, "a long …"},
+		{"a long message", 8, spinner.TruncateHead, "…message"},
+		{"/very/long/path/to/file.go", 16, spinner.TruncateMiddle, "/very/lo…file.go"},
+		{"世界世界世界", 6, spinner.TruncateTail, "世界…"},
+		{"世界世界世界", 6, spinner.TruncateHead, "…世界"},
+		{"éééé", 3, spinner.TruncateTail, "éé…"},
+		{"👩‍💻👩‍💻👩‍💻", 5, spinner.TruncateTail, "👩‍💻👩‍💻…"},
+		{"🇯🇵🇫🇷🇩🇪", 3, spinner.TruncateHead, "…🇩🇪"},
+		{"\033[31mred text\033[0m", 4, spinner.TruncateTail, "\033[31mred…\033[0m"},
+		{"\033[31mred\033[0m plain", 5, spinner.TruncateHead, "\033[31m…\033[0mlain"},
+		{"anything", 1, spinner.TruncateTail, "…"},
+		{"anything", 0, spinner.TruncateTail, ""},
+	}
+	for _, tt := range tests {
+		if got := spinner.Truncate(tt.text, tt.width, tt.mode); got != tt.want {
+			t.Errorf("Truncate(%q, %d, %v) = %q, want %q", tt.text, tt.width, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestMaxLineLength(t *testing.T) {
+	var lines []string
+	s := spinner.New(
+		spinner.WithWriter(&bytes.Buffer{}),
+		spinner.WithColorMode(spinner.ColorNever),
+		spinner.WithFrames([]string{"-"}),
+		spinner.WithMessage("/usr/local/share/very/long/path/main.go"),
+		spinner.WithSuffix("[3/9]"),
+		spinner.WithMaxLineLength(24),
+		spinner.WithTruncate(spinner.TruncateMiddle),
+		spinner.WithRecorder(&lines),
+	)
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+	if want := "- /usr/loc…main.go [3/9]"; len(lines) == 0 || lines[0] != want {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
@@ -0,0 +1,102 @@
+package spinner
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// screen is a minimal virtual terminal. It understands carriage returns,
+// newlines and the cursor movement and erase sequences spinners write, and
+// keeps the text left on screen. Other escape sequences are ignored.
+type screen struct {
+	lines    [][]rune
+	row, col int
+	saved    [2]int
+}
+
+func (sc *screen) Write(p []byte) (int, error) {
+	s := string(p)
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\r':
+			sc.col = 0
+			i++
+		case s[i] == '\n':
+			sc.row, sc.col = sc.row+1, 0
+			i++
+		case strings.HasPrefix(s[i:], "\033["):
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			sc.csi(s[i+2:j], s[j])
+			i = j + 1
+		case strings.HasPrefix(s[i:], "\033]"):
+			i += strings.IndexByte(s[i:], '\a') + 1
+		default:
+			r, n := utf8.DecodeRuneInString(s[i:])
+			sc.put(r)
+			i += n
+		}
+	}
+	return len(p), nil
+}
+
+func (sc *screen) csi(params string, final byte) {
+	args := strings.Split(params, ";")
+	arg := func(i, def int) int {
+		if i < len(args) {
+			if n, err := strconv.Atoi(args[i]); err == nil {
+				return n
+			}
+		}
+		return def
+	}
+	switch final {
+	case 'A':
+		sc.row = max(sc.row-arg(0, 1), 0)
+	case 'H':
+		sc.row, sc.col = arg(0, 1)-1, arg(1, 1)-1
+	case 'K':
+		if sc.row < len(sc.lines) {
+			line := sc.lines[sc.row]
+			if params == "2" {
+				line = line[:0]
+			} else if sc.col < len(line) {
+				line = line[:sc.col]
+			}
+			sc.lines[sc.row] = line
+		}
+	case 's':
+		sc.saved = [2]int{sc.row, sc.col}
+	case 'u':
+		sc.row, sc.col = sc.saved[0], sc.saved[1]
+	}
+}
+
+func (sc *screen) put(r rune) {
+	for len(sc.lines) <= sc.row {
+		sc.lines = append(sc.lines, nil)
+	}
+	line := sc.lines[sc.row]
+	for len(line) < sc.col {
+		line = append(line, ' ')
+	}
+	if sc.col < len(line) {
+		line[sc.col] = r
+	} else {
+		line = append(line, r)
+	}
+	sc.lines[sc.row] = line
+	sc.col++
+}
+
+// String returns the screen's lines with trailing blanks removed.
+func (sc *screen) String() string {
+	lines := make([]string, len(sc.lines))
+	for i, line := range sc.lines {
+		lines[i] = strings.TrimRight(string(line), " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
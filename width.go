@@ -0,0 +1,182 @@
+package spinner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// defaultEmojiWidth is the width of a narrow character followed by U+FE0F,
+// the emoji variation selector, as in "❤️". Most terminals draw such a
+// character as a two-column emoji, but some keep it at one column.
+const defaultEmojiWidth = 2
+
+// WithEmojiWidth sets the number of columns, 1 or 2, that the spinner
+// counts for a character that is narrow on its own but followed by U+FE0F,
+// such as the last frame of Hearts. The default of 2 matches most
+// terminals; terminals that draw these characters in a single column need
+// 1, or the spinner leaves stray characters behind when the line shrinks.
+// Any other n leaves the width as it was, and NewWithError reports it.
+func WithEmojiWidth(n int) Option {
+	return func(s *Spinner) {
+		if n != 1 && n != 2 {
+			s.optErrs = append(s.optErrs, fmt.Errorf("spinner: WithEmojiWidth(%d): width must be 1 or 2", n))
+			return
+		}
+		s.emojiWidth = n
+	}
+}
+
+// WithWidthFunc sets the function the spinner measures text with, in
+// columns, in place of its own, which handles wide characters, combining
+// marks and common emoji but not every grapheme cluster of complex scripts
+// or emoji sequences. Every width the spinner needs to draw, clear and
+// truncate its line is taken from f, which is passed text without escape
+// sequences. Nil restores the built-in measure.
+func WithWidthFunc(f func(string) int) Option {
+	return func(s *Spinner) {
+		s.widthFunc = f
+	}
+}
+
+// width returns the columns text, which may contain escape sequences,
+// takes up, as measured by the WithWidthFunc func if there is one.
+func (s *Spinner) width(text string) int {
+	if s.widthFunc != nil {
+		return s.widthFunc(stripEscapes(text))
+	}
+	return displayWidthEmoji(text, s.emojiWidth)
+}
+
+// plainWidth is width for text known to hold no escape sequences.
+func (s *Spinner) plainWidth(text string) int {
+	if s.widthFunc != nil {
+		return s.widthFunc(text)
+	}
+	return stringWidthEmoji(text, s.emojiWidth)
+}
+
+// frameWidths is frameWidths measured with the spinner's width func.
+func (s *Spinner) frameWidths(frames []string) []int {
+	if s.widthFunc == nil {
+		return frameWidths(frames, s.emojiWidth)
+	}
+	widths := make([]int, len(frames))
+	for i, f := range frames {
+		widths[i] = s.widthFunc(f[strings.LastIndexByte(f, '\n')+1:])
+	}
+	return widths
+}
+
+// stringWidth returns the number of terminal columns s occupies.
+func stringWidth(s string) int {
+	return stringWidthEmoji(s, defaultEmojiWidth)
+}
+
+// stringWidthEmoji is stringWidth with emoji columns for each narrow
+// character followed by U+FE0F.
+func stringWidthEmoji(s string, emoji int) int {
+	w, narrow := 0, false
+	for _, r := range s {
+		if r == '\ufe0f' && narrow {
+			w += emoji - 1
+		}
+		rw := runeWidth(r)
+		w += rw
+		narrow = rw == 1
+	}
+	return w
+}
+
+// frameWidths returns the display width of each frame, or of its last line
+// for a frame of several lines, which is the line the message follows.
+func frameWidths(frames []string, emoji int) []int {
+	widths := make([]int, len(frames))
+	for i, f := range frames {
+		widths[i] = stringWidthEmoji(f[strings.LastIndexByte(f, '\n')+1:], emoji)
+	}
+	return widths
+}
+
+// padding returns n spaces, or the empty string if n is not positive.
+func padding(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat(" ", n)
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20, r >= 0x7f && r < 0xa0:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// wideRanges lists East Asian wide/fullwidth and emoji presentation ranges.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115f},
+	{0x231a, 0x231b},
+	{0x23e9, 0x23ec},
+	{0x23f0, 0x23f0},
+	{0x23f3, 0x23f3},
+	{0x25fd, 0x25fe},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x26aa, 0x26ab},
+	{0x26bd, 0x26be},
+	{0x26c4, 0x26c5},
+	{0x26ce, 0x26ce},
+	{0x26d4, 0x26d4},
+	{0x26ea, 0x26ea},
+	{0x26f2, 0x26f5},
+	{0x26fa, 0x26fd},
+	{0x2705, 0x2705},
+	{0x270a, 0x270b},
+	{0x2728, 0x2728},
+	{0x274c, 0x274c},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27b0, 0x27b0},
+	{0x27bf, 0x27bf},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe30, 0xfe4f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff},
+	{0x1f900, 0x1f9ff},
+	{0x1fa70, 0x1faff},
+	{0x20000, 0x3fffd},
+}
+
+func isWide(r rune) bool {
+	if r < wideRanges[0].lo {
+		return false
+	}
+	lo, hi := 0, len(wideRanges)
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch {
+		case r < wideRanges[m].lo:
+			hi = m
+		case r > wideRanges[m].hi:
+			lo = m + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package spinner
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"-", 1},
+		{"...", 3},
+		{"⠋", 1},
+		{"⢀⠀", 2},
+		{"◴", 1},
+		{"日本", 4},
+		{"🌑", 2},
+		{"😄 ", 3},
+		{"❤️ ", 3},
+		{"❤", 1},
+		{"é", 1},
+		{"\x1b", 0},
+	}
+	for _, tt := range tests {
+		if got := stringWidth(tt.in); got != tt.want {
+			t.Errorf("stringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSetFramesWidths(t *testing.T) {
+	s := New(WithFrames(Line))
+	s.index = 3
+	s.SetFrames(Moon)
+	if s.index != 0 {
+		t.Errorf("index = %d after SetFrames, want 0", s.index)
+	}
+	if len(s.widths) != len(Moon) {
+		t.Fatalf("len(widths) = %d, want %d", len(s.widths), len(Moon))
+	}
+	for i, w := range s.widths {
+		if w != 2 {
+			t.Errorf("widths[%d] = %d, want 2", i, w)
+		}
+	}
+}
+
+func TestEmojiWidth(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames []string
+		opts   []Option
+		want   []int
+	}{
+		{"hearts", Hearts, nil, []int{3, 3, 3, 3, 3}},
+		{"hearts narrow", Hearts, []Option{WithEmojiWidth(1)}, []int{3, 3, 3, 3, 2}},
+		{"smiley", Smiley, nil, []int{3, 3}},
+		{"smiley narrow", Smiley, []Option{WithEmojiWidth(1)}, []int{3, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(append([]Option{WithFrames(tt.frames)}, tt.opts...)...)
+			if !reflect.DeepEqual(s.widths, tt.want) {
+				t.Errorf("widths = %v, want %v", s.widths, tt.want)
+			}
+			s = New(append([]Option{WithSegments(Segment{Frames: tt.frames})}, tt.opts...)...)
+			if !reflect.DeepEqual(s.segments[0].widths, tt.want) {
+				t.Errorf("segment widths = %v, want %v", s.segments[0].widths, tt.want)
+			}
+		})
+	}
+	for _, n := range []int{-1, 0, 3} {
+		if _, err := NewWithError(WithFrames(Hearts), WithEmojiWidth(n)); err == nil {
+			t.Errorf("WithEmojiWidth(%d): NewWithError returned no error", n)
+		}
+		s := New(WithFrames(Hearts), WithEmojiWidth(n))
+		if want := []int{3, 3, 3, 3, 3}; !reflect.DeepEqual(s.widths, want) {
+			t.Errorf("WithEmojiWidth(%d): widths = %v, want %v", n, s.widths, want)
+		}
+	}
+	if got := displayWidth("\x1b[31m❤️\x1b[0m"); got != 2 {
+		t.Errorf("displayWidth of colored heart = %d, want 2", got)
+	}
+}
+
+func BenchmarkFrameWidth(b *testing.B) {
+	for _, bc := range []struct {
+		name   string
+		frames []string
+	}{
+		{"Material", Material},
+		{"Hearts", Hearts},
+		{"Clock", Clock},
+	} {
+		b.Run(bc.name+"/uncached", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = stringWidth(bc.frames[i%len(bc.frames)])
+			}
+		})
+		widths := frameWidths(bc.frames, defaultEmojiWidth)
+		b.Run(bc.name+"/cached", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = widths[i%len(widths)]
+			}
+		})
+	}
+}
+
+func TestMaxFrameWidth(t *testing.T) {
+	tests := []struct {
+		frames []string
+		want   int
+	}{
+		{Dots1, 1},
+		{Dots12, 2},
+		{SimpleDots, 3},
+		{Moon, 2},
+		{Hearts, 3},
+		{Balloon, 1},
+		{Material, 20},
+		{[]string{"a", "日本語", "bc"}, 6},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := New(WithFrames(tt.frames)).MaxFrameWidth(); got != tt.want {
+			t.Errorf("MaxFrameWidth(%q) = %d, want %d", tt.frames, got, tt.want)
+		}
+	}
+}
+
+func TestWidthFunc(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	var escapes []string
+	// Every character is two columns, to tell this measure from the
+	// built-in one.
+	wide := func(text string) int {
+		if strings.Contains(text, "\033") {
+			escapes = append(escapes, text)
+		}
+		return 2 * utf8.RuneCountInString(text)
+	}
+	s := New(WithWriter(io.Discard), WithWidthFunc(wide), WithFrames([]string{"ab", "c"}),
+		WithColorMode(ColorAlways), WithMessage("hello world"), WithMaxLineLength(20))
+	if want := []int{4, 2}; !reflect.DeepEqual(s.widths, want) {
+		t.Errorf("frame widths = %v, want %v", s.widths, want)
+	}
+	// 4 for the frame and 2 for the space leave 14 columns: six characters
+	// and the ellipsis.
+	line, w := s.render(true)
+	if !strings.HasSuffix(line, " hello …") || w != 20 {
+		t.Errorf("render = %q, width %d, want it cut to \"hello …\" and 20 columns", line, w)
+	}
+	if len(escapes) > 0 {
+		t.Errorf("width func passed escape sequences: %q", escapes)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package spinner
+
+import "os"
+
+// winsizeColumns is not supported on this system, so the width of the
+// terminal is taken from COLUMNS alone.
+func winsizeColumns(f *os.File) int {
+	return 0
+}
+
+// fdColumns is not supported on this system either.
+func fdColumns(fd uintptr) int {
+	return 0
+}
+
+// fdIsTerminal cannot tell on this system what a bare file descriptor is
+// open on, so writers that are not an *os.File are never taken for a
+// terminal unless WithForceTTY says so.
+func fdIsTerminal(fd uintptr) bool {
+	return false
+}
@@ -0,0 +1,43 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package spinner
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsizeColumns returns the width of the terminal f in columns, or zero
+// if it cannot be found.
+func winsizeColumns(f *os.File) int {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return 0
+	}
+	var n int
+	if err := conn.Control(func(fd uintptr) { n = fdColumns(fd) }); err != nil {
+		return 0
+	}
+	return n
+}
+
+// fdColumns returns the width in columns of the terminal open as fd, or
+// zero if it cannot be found.
+func fdColumns(fd uintptr) int {
+	var ws struct{ row, col, xpixel, ypixel uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.col)
+}
+
+// fdIsTerminal reports whether fd is open on a character device.
+func fdIsTerminal(fd uintptr) bool {
+	var st syscall.Stat_t
+	if err := syscall.Fstat(int(fd), &st); err != nil {
+		return false
+	}
+	return uint32(st.Mode)&syscall.S_IFMT == syscall.S_IFCHR
+}
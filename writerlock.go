@@ -0,0 +1,133 @@
+package spinner
+
+import (
+	"io"
+	"sync"
+)
+
+// A WriterLock serializes output to a writer shared by several spinners, so
+// that a redraw, a final message, or a line printed above one spinner is
+// never split by another's. Spinners lock it around each group of writes
+// that must reach the writer together.
+//
+// Serializing writes keeps escape sequences intact but does not stop two
+// inline spinners from drawing over each other's line; give each spinner
+// its own row with WithScreenPosition for that. Programs that write to the
+// same writer themselves can hold the lock while they do.
+type WriterLock struct {
+	mu sync.Mutex
+}
+
+// Lock locks l.
+func (l *WriterLock) Lock() { l.mu.Lock() }
+
+// Unlock unlocks l.
+func (l *WriterLock) Unlock() { l.mu.Unlock() }
+
+// writerLockEntry is the lock shared by the spinners writing to a writer.
+// The entry is dropped once no running spinner uses it, so that the writer
+// is not kept alive, unless WriterLockFor has handed the lock out.
+type writerLockEntry struct {
+	lock   *WriterLock
+	refs   int  // running spinners holding the entry
+	pinned bool // returned by WriterLockFor
+}
+
+var writerLocks = struct {
+	sync.Mutex
+	locks map[io.Writer]*writerLockEntry
+}{locks: make(map[io.Writer]*writerLockEntry)}
+
+// WriterLockFor returns the lock that spinners writing to w use unless they
+// are given one with WithWriterLock. Every call with the same writer returns
+// the same lock. A writer that cannot be compared, such as a func-backed
+// value type, gets a new lock each time.
+//
+// The lock, and with it w, stays registered for the life of the process,
+// which suits long-lived writers such as os.Stderr. Spinners only register
+// their writer's lock while they run.
+func WriterLockFor(w io.Writer) *WriterLock {
+	if !writerComparable(w) {
+		return new(WriterLock)
+	}
+	writerLocks.Lock()
+	defer writerLocks.Unlock()
+	e, ok := writerLocks.locks[w]
+	if !ok {
+		e = &writerLockEntry{lock: new(WriterLock)}
+		writerLocks.locks[w] = e
+	}
+	e.pinned = true
+	return e.lock
+}
+
+// sharedWriterLock returns the lock registered for w, or l if there is
+// none.
+func sharedWriterLock(w io.Writer, l *WriterLock) *WriterLock {
+	if !writerComparable(w) {
+		return l
+	}
+	writerLocks.Lock()
+	defer writerLocks.Unlock()
+	if e, ok := writerLocks.locks[w]; ok {
+		return e.lock
+	}
+	return l
+}
+
+// holdWriterLock registers the spinner's lock for its writer, or takes
+// over the one already registered, for as long as the spinner runs. It
+// does nothing for a lock given with WithWriterLock. The caller must hold
+// mu.
+func (s *Spinner) holdWriterLock() {
+	if !s.sharedLock || s.lockHeld || !writerComparable(s.writer) {
+		return
+	}
+	writerLocks.Lock()
+	defer writerLocks.Unlock()
+	e, ok := writerLocks.locks[s.writer]
+	if !ok {
+		e = &writerLockEntry{lock: s.writerLock}
+		writerLocks.locks[s.writer] = e
+	}
+	e.refs++
+	s.writerLock, s.lockHeld = e.lock, true
+}
+
+// releaseWriterLock undoes holdWriterLock, dropping the registration once
+// no running spinner or WriterLockFor caller needs it. The caller must
+// hold mu.
+func (s *Spinner) releaseWriterLock() {
+	if !s.lockHeld {
+		return
+	}
+	s.lockHeld = false
+	writerLocks.Lock()
+	defer writerLocks.Unlock()
+	e, ok := writerLocks.locks[s.writer]
+	if !ok {
+		return
+	}
+	if e.refs--; e.refs == 0 && !e.pinned {
+		delete(writerLocks.locks, s.writer)
+	}
+}
+
+// idleWriterLock brings the lock of a spinner that is not running up to
+// date with the one registered for its writer, if any, before it writes.
+// The caller must hold mu.
+func (s *Spinner) idleWriterLock() {
+	if s.sharedLock && !s.lockHeld {
+		s.writerLock = sharedWriterLock(s.writer, s.writerLock)
+	}
+}
+
+// WithWriterLock sets the lock the spinner holds while writing, replacing
+// the one WriterLockFor would give its writer. Share a lock between
+// spinners whose writers are different values that reach the same output,
+// such as two wrappers around os.Stderr.
+func WithWriterLock(l *WriterLock) Option {
+	return func(s *Spinner) {
+		s.writerLock = l
+	}
+}
@@ -0,0 +1,73 @@
+package spinner_test
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tmc/spinner"
+)
+
+// overlapWriter records whether two Writes were ever in progress at once.
+type overlapWriter struct {
+	inFlight atomic.Int32
+	overlap  atomic.Bool
+}
+
+func (w *overlapWriter) Write(p []byte) (int, error) {
+	if w.inFlight.Add(1) > 1 {
+		w.overlap.Store(true)
+	}
+	time.Sleep(50 * time.Microsecond)
+	w.inFlight.Add(-1)
+	return len(p), nil
+}
+
+func TestSharedWriter(t *testing.T) {
+	t.Setenv("TERM", "xterm")
+	w := new(overlapWriter)
+	a := spinner.New(spinner.WithWriter(w), spinner.WithInterval(time.Millisecond))
+	b := spinner.New(spinner.WithWriter(w), spinner.WithInterval(time.Millisecond))
+	a.Start()
+	b.Start()
+	for i := 0; i < 20; i++ {
+		a.LogWriter().Write([]byte("from a"))
+		b.LogWriter().Write([]byte("from b"))
+		time.Sleep(time.Millisecond)
+	}
+	a.Success("a done")
+	b.Fail("b failed")
+	if w.overlap.Load() {
+		t.Error("spinners sharing a writer wrote to it concurrently")
+	}
+}
+
+func TestWriterLockFor(t *testing.T) {
+	var x, y bytes.Buffer
+	if spinner.WriterLockFor(&x) != spinner.WriterLockFor(&x) {
+		t.Error("WriterLockFor returned different locks for the same writer")
+	}
+	if spinner.WriterLockFor(&x) == spinner.WriterLockFor(&y) {
+		t.Error("WriterLockFor returned the same lock for different writers")
+	}
+}
+
+func TestWriterLockReleased(t *testing.T) {
+	var buf bytes.Buffer
+	s := spinner.New(spinner.WithWriter(&buf), spinner.WithInterval(time.Millisecond))
+	s.Start()
+	if !spinner.WriterRegistered(&buf) {
+		t.Fatal("no lock registered for the writer of a running spinner")
+	}
+	s.Stop()
+	if spinner.WriterRegistered(&buf) {
+		t.Error("writer still registered after the spinner stopped")
+	}
+	s.Start()
+	s.Reset(spinner.WithWriter(io.Discard))
+	if spinner.WriterRegistered(&buf) {
+		t.Error("writer still registered after the spinner was reset")
+	}
+}